@@ -0,0 +1,328 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+)
+
+// ===== DTO =====
+
+// Adjust — изменение остатка на Delta. Если задать ExpectedVersion или PrevUpdatedAt
+// и они не совпадут с сервером, вернётся *ConflictError.
+type Adjust struct {
+	ItemID          int64
+	LocationCode    string
+	Delta           int64
+	Reason          invpb.StockChangeReason
+	Reference       string
+	AllowNegative   bool
+	ExpectedVersion int64
+	PrevUpdatedAt   time.Time
+}
+
+// Set — установка точного on_hand.
+type Set struct {
+	ItemID          int64
+	LocationCode    string
+	OnHand          int64
+	Reason          invpb.StockChangeReason
+	Reference       string
+	ExpectedVersion int64
+	PrevUpdatedAt   time.Time
+}
+
+// LineResult — итог строки best-effort батча: Stock или Err (классы errorsx, как у RPC).
+type LineResult struct {
+	Stock Stock
+	Err   error
+}
+
+// Transfer — перемещение между локациями. InTransit=true — только отгрузка,
+// приход — через ReceiveTransfer.
+type Transfer struct {
+	ItemID              int64
+	From                string
+	To                  string
+	Quantity            int64
+	Reference           string
+	InTransit           bool
+	ExpectedFromVersion int64
+}
+
+type TransferInfo struct {
+	ID        string
+	ItemID    int64
+	From      string
+	To        string
+	Quantity  int64
+	State     invpb.TransferState
+	Reference string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MovementFilter — пустые поля не фильтруют. PageToken — из предыдущей MovementPage.
+type MovementFilter struct {
+	ItemID       int64
+	LocationCode string
+	Reasons      []invpb.StockChangeReason
+	Reference    string
+	Since        time.Time
+	Until        time.Time
+	PageSize     int32
+	PageToken    string
+}
+
+type Movement struct {
+	ID           int64
+	ItemID       int64
+	LocationCode string
+	Delta        int64
+	Before       int64
+	After        int64
+	Reason       invpb.StockChangeReason
+	Reference    string
+	Actor        string
+	CreatedAt    time.Time
+}
+
+type MovementPage struct {
+	Movements     []Movement // от новых к старым
+	NextPageToken string     // пусто — страниц больше нет
+}
+
+// ===== Операции =====
+// Все записи идут с ключом идемпотентности (см. WithIdempotencyKey), поэтому их безопасно повторять.
+
+func (c *Client) AdjustStock(ctx context.Context, a Adjust) (Stock, error) {
+	req := &invpb.AdjustStockRequest{
+		ItemId:          a.ItemID,
+		LocationCode:    a.LocationCode,
+		Delta:           a.Delta,
+		Reason:          a.Reason,
+		Reference:       a.Reference,
+		AllowNegative:   a.AllowNegative,
+		PrevUpdatedAt:   optTs(a.PrevUpdatedAt),
+		ExpectedVersion: a.ExpectedVersion,
+	}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return Stock{}, err
+	}
+	return call(ctx, c, invpb.StockAdminService_AdjustStock_FullMethodName, true, func(ctx context.Context) (Stock, error) {
+		resp, err := c.admin.AdjustStock(ctx, req)
+		if err != nil {
+			return Stock{}, err
+		}
+		return toStock(resp.GetStock()), nil
+	})
+}
+
+func (c *Client) SetStock(ctx context.Context, s Set) (Stock, error) {
+	req := &invpb.SetStockRequest{
+		ItemId:          s.ItemID,
+		LocationCode:    s.LocationCode,
+		NewAvailable:    s.OnHand,
+		Reason:          s.Reason,
+		Reference:       s.Reference,
+		PrevUpdatedAt:   optTs(s.PrevUpdatedAt),
+		ExpectedVersion: s.ExpectedVersion,
+	}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return Stock{}, err
+	}
+	return call(ctx, c, invpb.StockAdminService_SetStock_FullMethodName, true, func(ctx context.Context) (Stock, error) {
+		resp, err := c.admin.SetStock(ctx, req)
+		if err != nil {
+			return Stock{}, err
+		}
+		return toStock(resp.GetStock()), nil
+	})
+}
+
+// BatchAdjustStock — все строки или ни одной; ошибка строки приходит как ошибка вызова.
+func (c *Client) BatchAdjustStock(ctx context.Context, lines []Adjust) ([]Stock, error) {
+	req := &invpb.BatchAdjustStockRequest{Lines: toPBLines(lines), Mode: invpb.BatchMode_BATCH_MODE_ALL_OR_NOTHING}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return call(ctx, c, invpb.StockAdminService_BatchAdjustStock_FullMethodName, true, func(ctx context.Context) ([]Stock, error) {
+		resp, err := c.admin.BatchAdjustStock(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]Stock, 0, len(resp.GetStocks()))
+		for _, pb := range resp.GetStocks() {
+			out = append(out, toStock(pb))
+		}
+		return out, nil
+	})
+}
+
+// BatchAdjustStockBestEffort — применяются прошедшие проверки строки; результат по каждой строке.
+func (c *Client) BatchAdjustStockBestEffort(ctx context.Context, lines []Adjust) ([]LineResult, error) {
+	req := &invpb.BatchAdjustStockRequest{Lines: toPBLines(lines), Mode: invpb.BatchMode_BATCH_MODE_BEST_EFFORT}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return call(ctx, c, invpb.StockAdminService_BatchAdjustStock_FullMethodName, true, func(ctx context.Context) ([]LineResult, error) {
+		resp, err := c.admin.BatchAdjustStock(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]LineResult, len(lines))
+		for _, r := range resp.GetResults() {
+			i := int(r.GetIndex())
+			if i < 0 || i >= len(out) {
+				continue
+			}
+			if le := r.GetError(); le != nil {
+				out[i].Err = fromLineError(le)
+				continue
+			}
+			out[i].Stock = toStock(r.GetStock())
+		}
+		return out, nil
+	})
+}
+
+func (c *Client) TransferStock(ctx context.Context, t Transfer) (TransferInfo, Stock, error) {
+	req := &invpb.TransferStockRequest{
+		ItemId:              t.ItemID,
+		FromLocationCode:    t.From,
+		ToLocationCode:      t.To,
+		Quantity:            t.Quantity,
+		Reference:           t.Reference,
+		InTransit:           t.InTransit,
+		ExpectedFromVersion: t.ExpectedFromVersion,
+	}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	res, err := call(ctx, c, invpb.StockAdminService_TransferStock_FullMethodName, true, func(ctx context.Context) (*invpb.TransferStockResponse, error) {
+		return c.admin.TransferStock(ctx, req)
+	})
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	return toTransferInfo(res.GetTransfer()), toStock(res.GetStock()), nil
+}
+
+// ReceiveTransfer — приход перемещения «в пути»; повторный вызов безопасен.
+func (c *Client) ReceiveTransfer(ctx context.Context, transferID string) (TransferInfo, Stock, error) {
+	req := &invpb.ReceiveTransferRequest{TransferId: transferID}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	res, err := call(ctx, c, invpb.StockAdminService_ReceiveTransfer_FullMethodName, true, func(ctx context.Context) (*invpb.ReceiveTransferResponse, error) {
+		return c.admin.ReceiveTransfer(ctx, req)
+	})
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	return toTransferInfo(res.GetTransfer()), toStock(res.GetStock()), nil
+}
+
+// CancelTransfer — вернуть товар «в пути» на источник; повторный вызов безопасен.
+func (c *Client) CancelTransfer(ctx context.Context, transferID string) (TransferInfo, Stock, error) {
+	req := &invpb.CancelTransferRequest{TransferId: transferID}
+	ctx, err := c.adminCtx(ctx)
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	res, err := call(ctx, c, invpb.StockAdminService_CancelTransfer_FullMethodName, true, func(ctx context.Context) (*invpb.CancelTransferResponse, error) {
+		return c.admin.CancelTransfer(ctx, req)
+	})
+	if err != nil {
+		return TransferInfo{}, Stock{}, err
+	}
+	return toTransferInfo(res.GetTransfer()), toStock(res.GetStock()), nil
+}
+
+func (c *Client) ListMovements(ctx context.Context, f MovementFilter) (MovementPage, error) {
+	req := &invpb.ListStockMovementsRequest{
+		ItemId:       f.ItemID,
+		LocationCode: f.LocationCode,
+		Reasons:      f.Reasons,
+		Reference:    f.Reference,
+		Since:        optTs(f.Since),
+		Until:        optTs(f.Until),
+		PageSize:     f.PageSize,
+		PageToken:    f.PageToken,
+	}
+	return call(ctx, c, invpb.StockAdminService_ListStockMovements_FullMethodName, true, func(ctx context.Context) (MovementPage, error) {
+		resp, err := c.admin.ListStockMovements(ctx, req)
+		if err != nil {
+			return MovementPage{}, err
+		}
+		page := MovementPage{
+			Movements:     make([]Movement, 0, len(resp.GetMovements())),
+			NextPageToken: resp.GetNextPageToken(),
+		}
+		for _, m := range resp.GetMovements() {
+			page.Movements = append(page.Movements, Movement{
+				ID:           m.GetId(),
+				ItemID:       m.GetItemId(),
+				LocationCode: m.GetLocationCode(),
+				Delta:        m.GetDelta(),
+				Before:       m.GetBefore(),
+				After:        m.GetAfter(),
+				Reason:       m.GetReason(),
+				Reference:    m.GetReference(),
+				Actor:        m.GetActor(),
+				CreatedAt:    toTime(m.GetCreatedAt()),
+			})
+		}
+		return page, nil
+	})
+}
+
+// ===== Маппинг =====
+
+func toPBLines(lines []Adjust) []*invpb.BatchAdjustLine {
+	out := make([]*invpb.BatchAdjustLine, 0, len(lines))
+	for _, a := range lines {
+		out = append(out, &invpb.BatchAdjustLine{
+			ItemId:          a.ItemID,
+			LocationCode:    a.LocationCode,
+			Delta:           a.Delta,
+			Reason:          a.Reason,
+			Reference:       a.Reference,
+			AllowNegative:   a.AllowNegative,
+			PrevUpdatedAt:   optTs(a.PrevUpdatedAt),
+			ExpectedVersion: a.ExpectedVersion,
+		})
+	}
+	return out
+}
+
+func toTransferInfo(pb *invpb.Transfer) TransferInfo {
+	return TransferInfo{
+		ID:        pb.GetTransferId(),
+		ItemID:    pb.GetItemId(),
+		From:      pb.GetFromLocationCode(),
+		To:        pb.GetToLocationCode(),
+		Quantity:  pb.GetQuantity(),
+		State:     pb.GetState(),
+		Reference: pb.GetReference(),
+		CreatedAt: toTime(pb.GetCreatedAt()),
+		UpdatedAt: toTime(pb.GetUpdatedAt()),
+	}
+}
+
+// optTs — нулевое время -> поле не задано.
+func optTs(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
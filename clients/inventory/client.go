@@ -1,6 +1,349 @@
+// Package inventory — переиспользуемый SDK к inventory-svc: Catalog, Order и др. берут один
+// и тот же клиент вместо своих копий.
+//
+// Ошибки — классы pkg/errorsx (проверять через errorsx.IsNotFound(err) и т.п.);
+// конфликт версий — *ConflictError, нарушения по полям — *errorsx.ValidationError.
 package inventory
 
-// # переиспользуемые SDK-клиенты к сервисам
-// │  └─ inventory/                 # Catalog, Order и др. берут один и тот же клиент
-// │     └─ client.go               # (наш outbound-клиент сюда перенесём)
-// ├─ pkg/                          # инфра-утилиты (без домена)
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// maxBatchSize — предел id в одном BatchGetStock, если сервер не отдаёт GetLimits.
+const maxBatchSize = 500
+
+// ===== Опции =====
+
+type options struct {
+	retry    grpcx.RetryPolicy
+	breaker  *grpcx.CircuitBreaker
+	batchWin time.Duration // 0 — GetStock без склейки
+	batchMax int
+	maxBatch int // 0 — узнать у сервера (GetLimits)
+	batchPar int // параллельных запросов при нарезке больших BatchGetStock
+	hedge    *grpcx.HedgePolicy
+	actor    string
+	dialOpts []grpc.DialOption
+}
+
+func defaultOptions() options {
+	p := grpcx.DefaultRetryPolicy()
+	p.PerAttemptTimeout = 2 * time.Second
+	// Не больше 10% повторов от трафика (плюс 10/с), чтобы не добивать лежащий inventory.
+	p.Budget = grpcx.NewRetryBudget(0.1, 10)
+	return options{retry: p, breaker: grpcx.NewCircuitBreaker(grpcx.DefaultBreakerPolicy()), batchPar: 4}
+}
+
+// Option — функциональная опция клиента.
+type Option func(*options)
+
+// WithTimeout — дедлайн одной попытки (общий дедлайн задаёт ctx вызывающего).
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.retry.PerAttemptTimeout = d
+		}
+	}
+}
+
+// WithRetries — сколько раз повторять временные ошибки (UNAVAILABLE, RESOURCE_EXHAUSTED,
+// DEADLINE_EXCEEDED попытки). 0 — без повторов.
+func WithRetries(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.retry.MaxAttempts = n + 1
+		}
+	}
+}
+
+// WithBackoff — экспоненциальная пауза между попытками: base, 2*base, ... не больше max (с jitter).
+func WithBackoff(base, max time.Duration) Option {
+	return func(o *options) {
+		if base > 0 {
+			o.retry.InitialBackoff = base
+		}
+		if max >= o.retry.InitialBackoff {
+			o.retry.MaxBackoff = max
+		}
+	}
+}
+
+// WithRetryPolicy — политика ретраев целиком (заменяет WithTimeout/WithRetries/WithBackoff
+// и бюджет по умолчанию).
+func WithRetryPolicy(p grpcx.RetryPolicy) Option {
+	return func(o *options) { o.retry = p }
+}
+
+// WithCircuitBreaker — свой автомат (например, общий для нескольких клиентов одного target
+// или с другими порогами). nil — без автомата. По умолчанию — grpcx.DefaultBreakerPolicy:
+// пока inventory лежит, вызовы сразу получают errorsx.KindUnavailable, а не ждут все ретраи.
+func WithCircuitBreaker(b *grpcx.CircuitBreaker) Option {
+	return func(o *options) { o.breaker = b }
+}
+
+// WithBatching — склеивать параллельные GetStock за окно window в один BatchGetStock
+// (по location_code и as_of), не больше maxSize id в батче; одинаковые запросы в полёте
+// выполняются один раз. Полезно для кода, который ходит за остатками по одному товару.
+func WithBatching(window time.Duration, maxSize int) Option {
+	return func(o *options) {
+		if window <= 0 {
+			return
+		}
+		o.batchWin = window
+		o.batchMax = maxSize
+		if maxSize <= 0 || maxSize > maxBatchSize {
+			o.batchMax = maxBatchSize
+		}
+	}
+}
+
+// WithMaxBatch — размер частей, на которые режутся большие BatchGetStock. По умолчанию
+// клиент один раз спрашивает лимит у сервера (GetLimits).
+func WithMaxBatch(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxBatch = n
+		}
+	}
+}
+
+// WithBatchParallelism — сколько частей большого BatchGetStock читать одновременно (по умолчанию 4).
+func WithBatchParallelism(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.batchPar = n
+		}
+	}
+}
+
+// WithHedging — для GetStock/BatchGetStock: если ответа нет дольше перцентиля недавних
+// задержек, уходит вторая такая же попытка, побеждает первый ответ. Доля копий ограничена
+// p.MaxRatio, чтобы не перегружать inventory. По умолчанию выключено.
+func WithHedging(p grpcx.HedgePolicy) Option {
+	return func(o *options) { o.hedge = &p }
+}
+
+// WithActor — имя вызывающего (логин/сервис) для admin-операций, metadata grpcx.MetadataActor.
+// Невалидное имя (не печатный ASCII, длиннее 128) — admin-вызовы падают с INVALID_ARGUMENT.
+func WithActor(actor string) Option {
+	return func(o *options) { o.actor = actor }
+}
+
+// WithDialOptions — опции соединения для Dial. Без них — insecure (внутренняя сеть).
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) { o.dialOpts = append(o.dialOpts, opts...) }
+}
+
+// ===== Клиент =====
+
+type Client struct {
+	read   invpb.StockServiceClient
+	admin  invpb.StockAdminServiceClient
+	conn   *grpc.ClientConn // не nil, только если соединение открыл Dial
+	target string           // префикс ключей автомата: target соединения, если он известен
+	opts   options
+	retry  *grpcx.Retrier
+	loader *loader       // nil — без WithBatching
+	hedger *grpcx.Hedger // nil — без WithHedging
+
+	limitMu  sync.Mutex
+	maxBatch int // лимит сервера; 0 — ещё не узнали
+}
+
+// New — клиент поверх готового соединения (например, уже обёрнутого интерсепторами).
+func New(conn grpc.ClientConnInterface, opts ...Option) *Client {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &Client{
+		read:     invpb.NewStockServiceClient(conn),
+		admin:    invpb.NewStockAdminServiceClient(conn),
+		opts:     o,
+		retry:    grpcx.NewRetrier(o.retry),
+		maxBatch: o.maxBatch,
+	}
+	if t, ok := conn.(interface{ Target() string }); ok {
+		c.target = t.Target()
+	}
+	if o.batchWin > 0 {
+		c.loader = newLoader(c, o.batchWin, o.batchMax)
+	}
+	if o.hedge != nil {
+		c.hedger = grpcx.NewHedger(*o.hedge)
+	}
+	return c
+}
+
+// Dial открывает соединение к target; закрывать через Close.
+func Dial(target string, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dialOpts := o.dialOpts
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("inventory: dial %s: %w", target, err)
+	}
+	c := New(conn, opts...)
+	c.conn = conn
+	return c, nil
+}
+
+// Close закрывает соединение, если его открыл Dial.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// ===== внутреннее =====
+
+// call — одна логическая операция method: попытки по политике клиента (grpcx.Retrier),
+// каждая — через автомат target+method, а для чтений с WithHedging — ещё и с копией
+// на хвосте задержек. retry=false — операция не идемпотентна, повторять нельзя:
+// одна попытка с таймаутом.
+func call[T any](ctx context.Context, c *Client, method string, retry bool, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx = grpcx.PropagateMetadata(ctx)
+	attempt := fn
+	if b := c.opts.breaker; b != nil {
+		attempt = func(ctx context.Context) (T, error) {
+			var v T // своя на каждую копию: при хедже их две одновременно
+			err := b.Do(ctx, c.target+method, func(ctx context.Context) error {
+				var err error
+				v, err = fn(ctx)
+				return err
+			})
+			return v, err
+		}
+	}
+	if _, ok := hedgedMethods[method]; ok && c.hedger != nil {
+		guarded := attempt
+		attempt = func(ctx context.Context) (T, error) {
+			return grpcx.Hedge(ctx, c.hedger, method, guarded)
+		}
+	}
+
+	var zero, out T
+	run := func(ctx context.Context) error {
+		v, err := attempt(ctx)
+		if err == nil {
+			out = v
+		}
+		return err
+	}
+	var err error
+	if retry {
+		err = c.retry.Do(ctx, run)
+	} else {
+		err = once(ctx, c.retry.Policy().PerAttemptTimeout, run)
+	}
+	if err == nil {
+		return out, nil
+	}
+	if ctx.Err() != nil {
+		return zero, ctx.Err()
+	}
+	return zero, fromStatus(err)
+}
+
+// hedgedMethods — чтения, которые можно дублировать (WithHedging).
+var hedgedMethods = map[string]struct{}{
+	invpb.StockService_GetStock_FullMethodName:      {},
+	invpb.StockService_BatchGetStock_FullMethodName: {},
+}
+
+func once(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	actx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(actx)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// batchLimit — лимит BatchGetStock: из WithMaxBatch или GetLimits (запоминается).
+// Сервер без GetLimits — maxBatchSize.
+func (c *Client) batchLimit(ctx context.Context) (int, error) {
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	if c.maxBatch > 0 {
+		return c.maxBatch, nil
+	}
+	var resp *invpb.GetLimitsResponse
+	err := c.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.read.GetLimits(ctx, &invpb.GetLimitsRequest{})
+		return err
+	})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		c.maxBatch = maxBatchSize
+	case err != nil:
+		// Не запоминаем: при следующем вызове спросим снова.
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, fromStatus(err)
+	case resp.GetMaxBatchSize() > 0:
+		c.maxBatch = int(resp.GetMaxBatchSize())
+	default:
+		c.maxBatch = maxBatchSize
+	}
+	return c.maxBatch, nil
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey — задать ключ идемпотентности admin-операции явно (например, ID строки
+// импорта). Без него SDK генерирует UUID на каждый вызов; повторы внутри вызова идут с тем же ключом.
+// Ключ не в форме UUID превращается в UUID детерминированно (grpcx.IdempotencyKeyFor).
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// adminCtx — metadata для admin-операций: ключ идемпотентности и actor.
+func (c *Client) adminCtx(ctx context.Context) (context.Context, error) {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	switch {
+	case key == "":
+		key = grpcx.NewUUID()
+	case grpcx.ValidateMetadata(grpcx.MetadataIdempotencyKey, key) != nil:
+		key = grpcx.IdempotencyKeyFor(key)
+	}
+	ctx, err := grpcx.WithIdempotencyKey(ctx, key)
+	if err != nil {
+		return ctx, err
+	}
+	if c.opts.actor != "" {
+		return grpcx.WithActor(ctx, c.opts.actor)
+	}
+	return ctx, nil
+}
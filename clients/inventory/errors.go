@@ -0,0 +1,81 @@
+package inventory
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// ConflictError — ABORTED с reason VERSION_CONFLICT: prev_updated_at/expected_version не совпали.
+// Current — актуальное состояние товара (если сервер его прислал): перечитывать не нужно.
+// errorsx.IsAborted(err) == true.
+type ConflictError struct {
+	Current *Stock
+	err     error
+}
+
+func (e *ConflictError) Error() string { return e.err.Error() }
+func (e *ConflictError) Unwrap() error { return e.err }
+
+// fromStatus переводит gRPC-ошибку в класс errorsx (grpcx.FromStatus: *errorsx.E с Code и
+// Retryable сервера, *errorsx.ValidationError из BadRequest, иначе сентинел по коду).
+// Конфликт версий (reason VERSION_CONFLICT или актуальный Stock в details) — *ConflictError;
+// прочие ABORTED — обычный класс errorsx, без Current.
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	// Ошибка уже в классах errorsx: локальная (grpcx.CircuitOpenError) или разобранная
+	// grpcx.ErrorsUnaryClientInterceptor на соединении. ABORTED всё же разбираем по статусу:
+	// текущий Stock конфликта есть только в его деталях.
+	st, ok := status.FromError(err)
+	if _, isE := errorsx.AsE(err); isE && (!ok || st.Code() != codes.Aborted) {
+		return err
+	}
+	if !ok {
+		return errorsx.Internalf("inventory: %v", err)
+	}
+	out := grpcx.FromStatus(st)
+	if st.Code() != codes.Aborted {
+		return out
+	}
+	ce := &ConflictError{err: out}
+	for _, d := range st.Details() {
+		if pb, ok := d.(*invpb.Stock); ok {
+			cur := toStock(pb)
+			ce.Current = &cur
+		}
+	}
+	if ce.Current == nil && errorsx.CodeOf(out) != codeVersionConflict {
+		return out
+	}
+	return ce
+}
+
+// codeVersionConflict — reason ErrorInfo/LineError.code конфликта версий (см. stock_admin.proto).
+const codeVersionConflict = "VERSION_CONFLICT"
+
+// fromLineError — ошибка строки best-effort батча в тех же классах, что и ошибка RPC.
+func fromLineError(le *invpb.LineError) error {
+	c := codes.Code(le.GetStatus())
+	switch {
+	case c == codes.Aborted && (le.GetCode() == codeVersionConflict || le.GetCurrent() != nil):
+		ce := &ConflictError{err: errorsx.Aborted(le.GetMessage())}
+		if le.GetCurrent() != nil {
+			cur := toStock(le.GetCurrent())
+			ce.Current = &cur
+		}
+		return ce
+	case len(le.GetViolations()) > 0:
+		ve := errorsx.NewValidation()
+		for _, v := range le.GetViolations() {
+			ve.Add(v.GetField(), v.GetCode(), v.GetMessage(), v.GetParams())
+		}
+		return ve
+	default:
+		return fromStatus(status.Error(c, le.GetMessage()))
+	}
+}
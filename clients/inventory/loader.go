@@ -0,0 +1,154 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// loader — dataloader для GetStock: вызовы за окно window собираются в один
+// BatchGetStock (PARTIAL) на каждую пару location_code + as_of, результаты раздаются
+// вызывающим. Одинаковые запросы, пока первый не завершился, ждут его результат (singleflight).
+type loader struct {
+	c       *Client
+	window  time.Duration
+	maxSize int           // батч такого размера уходит сразу, не дожидаясь окна
+	timeout time.Duration // потолок на весь батч со всеми повторами (см. batchTimeout)
+
+	mu      sync.Mutex
+	flights map[loadKey]*flight
+	pending map[batchKey]*pendingBatch
+}
+
+type batchKey struct {
+	locationCode string
+	asOf         int64 // UnixNano; 0 — текущие остатки
+}
+
+type loadKey struct {
+	batchKey
+	itemID int64
+}
+
+type flight struct {
+	done  chan struct{}
+	stock Stock
+	err   error
+}
+
+type pendingBatch struct {
+	ctx   context.Context // ctx первого вызывающего без отмены (значения/metadata): батч общий, его не отменяет один из них
+	ids   []int64
+	timer *time.Timer
+}
+
+func newLoader(c *Client, window time.Duration, maxSize int) *loader {
+	return &loader{
+		c:       c,
+		window:  window,
+		maxSize: maxSize,
+		timeout: batchTimeout(c.retry.Policy()),
+		flights: make(map[loadKey]*flight),
+		pending: make(map[batchKey]*pendingBatch),
+	}
+}
+
+// batchTimeout — все попытки по политике клиента плюс паузы между ними; без PerAttemptTimeout — 10s.
+// Свой дедлайн нужен батчу потому, что дедлайн первого вызывающего к нему не переходит.
+func batchTimeout(p grpcx.RetryPolicy) time.Duration {
+	if p.PerAttemptTimeout <= 0 {
+		return 10 * time.Second
+	}
+	n := time.Duration(max(p.MaxAttempts, 1))
+	return n*p.PerAttemptTimeout + (n-1)*p.MaxBackoff
+}
+
+func (l *loader) load(ctx context.Context, itemID int64, locationCode string, asOf time.Time) (Stock, error) {
+	// Невалидный id уронил бы общий BatchGetStock для всех, кто попал в окно.
+	if itemID <= 0 {
+		return Stock{}, errorsx.InvalidArgumentf("item_id must be > 0, got %d", itemID)
+	}
+	bk := batchKey{locationCode: locationCode}
+	if !asOf.IsZero() {
+		bk.asOf = asOf.UnixNano()
+	}
+	k := loadKey{batchKey: bk, itemID: itemID}
+
+	l.mu.Lock()
+	f, ok := l.flights[k]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		l.flights[k] = f
+		l.enqueueLocked(ctx, bk, itemID)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Stock{}, ctx.Err()
+	case <-f.done:
+		return f.stock, f.err
+	}
+}
+
+func (l *loader) enqueueLocked(ctx context.Context, bk batchKey, itemID int64) {
+	b := l.pending[bk]
+	if b == nil {
+		b = &pendingBatch{ctx: context.WithoutCancel(ctx)}
+		l.pending[bk] = b
+		b.timer = time.AfterFunc(l.window, func() { l.flush(bk, b) })
+	}
+	b.ids = append(b.ids, itemID)
+	if len(b.ids) >= l.maxSize {
+		b.timer.Stop()
+		delete(l.pending, bk)
+		go l.run(bk, b)
+	}
+}
+
+func (l *loader) flush(bk batchKey, b *pendingBatch) {
+	l.mu.Lock()
+	if l.pending[bk] != b {
+		l.mu.Unlock() // уже ушёл по размеру
+		return
+	}
+	delete(l.pending, bk)
+	l.mu.Unlock()
+	l.run(bk, b)
+}
+
+func (l *loader) run(bk batchKey, b *pendingBatch) {
+	var opts []ReadOption
+	if bk.asOf != 0 {
+		opts = append(opts, AsOf(time.Unix(0, bk.asOf)))
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, l.timeout)
+	res, err := l.c.batchGet(ctx, b.ids, bk.locationCode, invpb.BatchGetMode_BATCH_GET_MODE_PARTIAL, opts)
+	cancel()
+
+	found := make(map[int64]Stock, len(res.Stocks))
+	for _, s := range res.Stocks {
+		found[s.ItemID] = s
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range b.ids {
+		k := loadKey{batchKey: bk, itemID: id}
+		f := l.flights[k]
+		delete(l.flights, k)
+		switch s, ok := found[id]; {
+		case err != nil:
+			f.err = err
+		case ok:
+			f.stock = s
+		default:
+			f.err = errorsx.NotFoundf("item %d", id)
+		}
+		close(f.done)
+	}
+}
@@ -0,0 +1,363 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// ===== DTO =====
+
+type Stock struct {
+	ItemID    int64
+	Available int64 // OnHand - Reserved, по всем локациям (или по одной, если её передали в запросе)
+	Reserved  int64 // под активными резервами checkout
+	OnHand    int64 // физически на складах
+	InTransit int64 // едет между локациями, в Available не входит
+	Locations []StockLocation
+	UpdatedAt time.Time
+}
+
+// StockLocation — остаток по одной локации (склад/магазин/ПВЗ).
+type StockLocation struct {
+	LocationCode string
+	Available    int64
+	Reserved     int64
+	OnHand       int64
+	InTransit    int64 // едет В эту локацию
+	Version      int64 // для ExpectedVersion в admin-операциях
+	UpdatedAt    time.Time
+}
+
+// Location — остаток в локации code; ok=false, если у товара её нет.
+func (s Stock) Location(code string) (StockLocation, bool) {
+	for _, l := range s.Locations {
+		if l.LocationCode == code {
+			return l, true
+		}
+	}
+	return StockLocation{}, false
+}
+
+// PartialStocks — ответ BatchGetStockPartial: найденные товары и неизвестные inventory.
+type PartialStocks struct {
+	Stocks  []Stock // в порядке запроса
+	Missing []int64
+}
+
+type Reservation struct {
+	ID           string
+	ItemID       int64
+	LocationCode string
+	Quantity     int64
+	State        invpb.ReservationState
+	Reference    string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// ReserveRequest — удержать Quantity единиц на локации. TTL=0 — серверный дефолт (15m).
+type ReserveRequest struct {
+	ItemID       int64
+	LocationCode string
+	Quantity     int64
+	TTL          time.Duration
+	Reference    string // id заказа/корзины
+}
+
+// ReadOption — параметр чтения остатков.
+type ReadOption func(*readParams)
+
+type readParams struct {
+	asOf time.Time
+}
+
+// AsOf — остатки на момент t (по журналу движений; reserved/in_transit в ответе нулевые).
+func AsOf(t time.Time) ReadOption {
+	return func(p *readParams) { p.asOf = t }
+}
+
+func readParamsOf(opts []ReadOption) readParams {
+	var p readParams
+	for _, o := range opts {
+		o(&p)
+	}
+	return p
+}
+
+func (p readParams) asOfPB() *timestamppb.Timestamp {
+	if p.asOf.IsZero() {
+		return nil
+	}
+	return timestamppb.New(p.asOf)
+}
+
+// ===== Чтение =====
+
+// GetStock — один товар; locationCode опционален. Нет товара — errorsx.ErrNotFound.
+// С WithBatching параллельные вызовы уходят одним BatchGetStock.
+func (c *Client) GetStock(ctx context.Context, itemID int64, locationCode string, opts ...ReadOption) (Stock, error) {
+	p := readParamsOf(opts)
+	if c.loader != nil {
+		return c.loader.load(ctx, itemID, locationCode, p.asOf)
+	}
+	req := &invpb.GetStockRequest{ItemId: itemID, LocationCode: locationCode, AsOf: p.asOfPB()}
+	return call(ctx, c, invpb.StockService_GetStock_FullMethodName, true, func(ctx context.Context) (Stock, error) {
+		resp, err := c.read.GetStock(ctx, req)
+		if err != nil {
+			return Stock{}, err
+		}
+		return toStock(resp.GetStock()), nil
+	})
+}
+
+// BatchGetStock — строгий режим: нет хотя бы одного товара — errorsx.ErrNotFound на всё.
+func (c *Client) BatchGetStock(ctx context.Context, itemIDs []int64, locationCode string, opts ...ReadOption) ([]Stock, error) {
+	res, err := c.batchGet(ctx, itemIDs, locationCode, invpb.BatchGetMode_BATCH_GET_MODE_STRICT, opts)
+	return res.Stocks, err
+}
+
+// BatchGetStockPartial — найденные товары + список неизвестных (для листингов).
+func (c *Client) BatchGetStockPartial(ctx context.Context, itemIDs []int64, locationCode string, opts ...ReadOption) (PartialStocks, error) {
+	return c.batchGet(ctx, itemIDs, locationCode, invpb.BatchGetMode_BATCH_GET_MODE_PARTIAL, opts)
+}
+
+// batchGet — списки больше лимита сервера (GetLimits) режутся на части, которые читаются
+// параллельно (не больше WithBatchParallelism запросов) и склеиваются в исходном порядке.
+func (c *Client) batchGet(ctx context.Context, itemIDs []int64, locationCode string, mode invpb.BatchGetMode, opts []ReadOption) (PartialStocks, error) {
+	limit, err := c.batchLimit(ctx)
+	if err != nil {
+		return PartialStocks{}, err
+	}
+	if len(itemIDs) <= limit {
+		return c.batchGetOnce(ctx, itemIDs, locationCode, mode, opts)
+	}
+
+	// Повторы убираем до нарезки: сервер дедуплицирует только в пределах одного запроса.
+	unique := make([]int64, 0, len(itemIDs))
+	seen := make(map[int64]struct{}, len(itemIDs))
+	for _, id := range itemIDs {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	var chunks [][]int64
+	for len(unique) > 0 {
+		n := min(limit, len(unique))
+		chunks = append(chunks, unique[:n])
+		unique = unique[n:]
+	}
+
+	// Части читаются в PARTIAL: строгий режим проверяем по склеенному результату,
+	// чтобы NOT_FOUND перечислял все отсутствующие id, а не одной части.
+	parts := make([]PartialStocks, len(chunks))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, c.opts.batchPar)
+	for i, chunk := range chunks {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			break // одна из частей уже упала (или отменён вызывающий)
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			res, err := c.batchGetOnce(ctx, chunk, locationCode, invpb.BatchGetMode_BATCH_GET_MODE_PARTIAL, opts)
+			if err != nil {
+				errOnce.Do(func() { firstErr = err; cancel() })
+				return
+			}
+			parts[i] = res
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return PartialStocks{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return PartialStocks{}, err
+	}
+
+	var out PartialStocks
+	for _, p := range parts {
+		out.Stocks = append(out.Stocks, p.Stocks...)
+		out.Missing = append(out.Missing, p.Missing...)
+	}
+	if mode != invpb.BatchGetMode_BATCH_GET_MODE_PARTIAL && len(out.Missing) > 0 {
+		ids := make([]string, len(out.Missing))
+		for i, id := range out.Missing {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		return PartialStocks{}, errorsx.NotFoundf("item_id not found: %s", strings.Join(ids, ", "))
+	}
+	return out, nil
+}
+
+func (c *Client) batchGetOnce(ctx context.Context, itemIDs []int64, locationCode string, mode invpb.BatchGetMode, opts []ReadOption) (PartialStocks, error) {
+	p := readParamsOf(opts)
+	req := &invpb.BatchGetStockRequest{ItemIds: itemIDs, LocationCode: locationCode, Mode: mode, AsOf: p.asOfPB()}
+	return call(ctx, c, invpb.StockService_BatchGetStock_FullMethodName, true, func(ctx context.Context) (PartialStocks, error) {
+		resp, err := c.read.BatchGetStock(ctx, req)
+		if err != nil {
+			return PartialStocks{}, err
+		}
+		out := PartialStocks{Stocks: make([]Stock, 0, len(resp.GetStocks())), Missing: resp.GetMissingItemIds()}
+		for _, pb := range resp.GetStocks() {
+			out.Stocks = append(out.Stocks, toStock(pb))
+		}
+		return out, nil
+	})
+}
+
+// WatchStock вызывает fn на каждое обновление товаров itemIDs (сначала — текущее состояние).
+// При обрыве переподключается с последним resume_token, так что изменения не теряются
+// (возможны повторы). Возвращается при отмене ctx, ошибке fn или неретраибельной ошибке сервера.
+func (c *Client) WatchStock(ctx context.Context, itemIDs []int64, locationCode string, fn func(Stock) error) error {
+	ctx = grpcx.PropagateMetadata(ctx)
+	var token string
+	for attempt := 0; ; {
+		stream, err := c.read.WatchStock(ctx, &invpb.WatchStockRequest{
+			ItemIds:      itemIDs,
+			LocationCode: locationCode,
+			ResumeToken:  token,
+		})
+		for err == nil {
+			var ev *invpb.StockEvent
+			if ev, err = stream.Recv(); err != nil {
+				break
+			}
+			attempt = 0
+			if ev.GetResumeToken() != "" {
+				token = ev.GetResumeToken()
+			}
+			if ferr := fn(toStock(ev.GetStock())); ferr != nil {
+				return ferr
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// EOF — сервер закрыл поток (например, при рестарте): переподключаемся.
+		if !errors.Is(err, io.EOF) && status.Code(err) != codes.Unavailable {
+			return fromStatus(err)
+		}
+		attempt++
+		if werr := sleep(ctx, c.retry.Policy().Backoff(attempt)); werr != nil {
+			return werr
+		}
+	}
+}
+
+// ===== Резервы =====
+
+// Reserve не повторяется автоматически: каждый успешный вызов создаёт новый резерв.
+func (c *Client) Reserve(ctx context.Context, r ReserveRequest) (Reservation, Stock, error) {
+	req := &invpb.ReserveStockRequest{
+		ItemId:       r.ItemID,
+		LocationCode: r.LocationCode,
+		Quantity:     r.Quantity,
+		Reference:    r.Reference,
+	}
+	if r.TTL > 0 {
+		req.Ttl = durationpb.New(r.TTL)
+	}
+	res, err := call(ctx, c, invpb.StockService_ReserveStock_FullMethodName, false, func(ctx context.Context) (*invpb.ReserveStockResponse, error) {
+		return c.read.ReserveStock(ctx, req)
+	})
+	if err != nil {
+		return Reservation{}, Stock{}, err
+	}
+	return toReservation(res.GetReservation()), toStock(res.GetStock()), nil
+}
+
+// CommitReservation списывает резерв с on_hand; повторный вызов безопасен.
+func (c *Client) CommitReservation(ctx context.Context, reservationID string) (Reservation, Stock, error) {
+	req := &invpb.CommitReservationRequest{ReservationId: reservationID}
+	res, err := call(ctx, c, invpb.StockService_CommitReservation_FullMethodName, true, func(ctx context.Context) (*invpb.CommitReservationResponse, error) {
+		return c.read.CommitReservation(ctx, req)
+	})
+	if err != nil {
+		return Reservation{}, Stock{}, err
+	}
+	return toReservation(res.GetReservation()), toStock(res.GetStock()), nil
+}
+
+// ReleaseReservation отпускает резерв; повторный вызов безопасен.
+func (c *Client) ReleaseReservation(ctx context.Context, reservationID string) (Reservation, Stock, error) {
+	req := &invpb.ReleaseReservationRequest{ReservationId: reservationID}
+	res, err := call(ctx, c, invpb.StockService_ReleaseReservation_FullMethodName, true, func(ctx context.Context) (*invpb.ReleaseReservationResponse, error) {
+		return c.read.ReleaseReservation(ctx, req)
+	})
+	if err != nil {
+		return Reservation{}, Stock{}, err
+	}
+	return toReservation(res.GetReservation()), toStock(res.GetStock()), nil
+}
+
+// ===== Маппинг =====
+
+func toStock(pb *invpb.Stock) Stock {
+	if pb == nil {
+		return Stock{}
+	}
+	out := Stock{
+		ItemID:    pb.GetItemId(),
+		Available: pb.GetAvailable(),
+		Reserved:  pb.GetReserved(),
+		OnHand:    pb.GetOnHand(),
+		InTransit: pb.GetInTransit(),
+		Locations: make([]StockLocation, 0, len(pb.GetLocations())),
+		UpdatedAt: toTime(pb.GetUpdatedAt()),
+	}
+	for _, l := range pb.GetLocations() {
+		out.Locations = append(out.Locations, StockLocation{
+			LocationCode: l.GetLocationCode(),
+			Available:    l.GetAvailable(),
+			Reserved:     l.GetReserved(),
+			OnHand:       l.GetOnHand(),
+			InTransit:    l.GetInTransit(),
+			Version:      l.GetVersion(),
+			UpdatedAt:    toTime(l.GetUpdatedAt()),
+		})
+	}
+	return out
+}
+
+func toReservation(pb *invpb.Reservation) Reservation {
+	return Reservation{
+		ID:           pb.GetReservationId(),
+		ItemID:       pb.GetItemId(),
+		LocationCode: pb.GetLocationCode(),
+		Quantity:     pb.GetQuantity(),
+		State:        pb.GetState(),
+		Reference:    pb.GetReference(),
+		CreatedAt:    toTime(pb.GetCreatedAt()),
+		ExpiresAt:    toTime(pb.GetExpiresAt()),
+	}
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,847 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v4.25.3
+// source: inventory/v1/location.proto
+
+package invpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LocationType int32
+
+const (
+	LocationType_LOCATION_TYPE_UNSPECIFIED  LocationType = 0
+	LocationType_LOCATION_TYPE_WAREHOUSE    LocationType = 1 // склад
+	LocationType_LOCATION_TYPE_STORE        LocationType = 2 // магазин
+	LocationType_LOCATION_TYPE_PICKUP_POINT LocationType = 3 // пункт выдачи
+)
+
+// Enum value maps for LocationType.
+var (
+	LocationType_name = map[int32]string{
+		0: "LOCATION_TYPE_UNSPECIFIED",
+		1: "LOCATION_TYPE_WAREHOUSE",
+		2: "LOCATION_TYPE_STORE",
+		3: "LOCATION_TYPE_PICKUP_POINT",
+	}
+	LocationType_value = map[string]int32{
+		"LOCATION_TYPE_UNSPECIFIED":  0,
+		"LOCATION_TYPE_WAREHOUSE":    1,
+		"LOCATION_TYPE_STORE":        2,
+		"LOCATION_TYPE_PICKUP_POINT": 3,
+	}
+)
+
+func (x LocationType) Enum() *LocationType {
+	p := new(LocationType)
+	*p = x
+	return p
+}
+
+func (x LocationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LocationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_location_proto_enumTypes[0].Descriptor()
+}
+
+func (LocationType) Type() protoreflect.EnumType {
+	return &file_inventory_v1_location_proto_enumTypes[0]
+}
+
+func (x LocationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LocationType.Descriptor instead.
+func (LocationType) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{0}
+}
+
+type LocationStatus int32
+
+const (
+	LocationStatus_LOCATION_STATUS_UNSPECIFIED LocationStatus = 0
+	LocationStatus_LOCATION_STATUS_ACTIVE      LocationStatus = 1
+	LocationStatus_LOCATION_STATUS_INACTIVE    LocationStatus = 2 // остатки видны, но менять их нельзя
+)
+
+// Enum value maps for LocationStatus.
+var (
+	LocationStatus_name = map[int32]string{
+		0: "LOCATION_STATUS_UNSPECIFIED",
+		1: "LOCATION_STATUS_ACTIVE",
+		2: "LOCATION_STATUS_INACTIVE",
+	}
+	LocationStatus_value = map[string]int32{
+		"LOCATION_STATUS_UNSPECIFIED": 0,
+		"LOCATION_STATUS_ACTIVE":      1,
+		"LOCATION_STATUS_INACTIVE":    2,
+	}
+)
+
+func (x LocationStatus) Enum() *LocationStatus {
+	p := new(LocationStatus)
+	*p = x
+	return p
+}
+
+func (x LocationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LocationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_location_proto_enumTypes[1].Descriptor()
+}
+
+func (LocationStatus) Type() protoreflect.EnumType {
+	return &file_inventory_v1_location_proto_enumTypes[1]
+}
+
+func (x LocationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LocationStatus.Descriptor instead.
+func (LocationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{1}
+}
+
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Формат: <2-8 латинских заглавных>-<2-4 цифры>, например "MSK-01". Неизменяем.
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // до 128 символов
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"` // до 64 символов
+	Type          LocationType           `protobuf:"varint,4,opt,name=type,proto3,enum=inventory.v1.LocationType" json:"type,omitempty"`
+	Status        LocationStatus         `protobuf:"varint,5,opt,name=status,proto3,enum=inventory.v1.LocationStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_inventory_v1_location_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Location) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Location) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Location) GetType() LocationType {
+	if x != nil {
+		return x.Type
+	}
+	return LocationType_LOCATION_TYPE_UNSPECIFIED
+}
+
+func (x *Location) GetStatus() LocationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return LocationStatus_LOCATION_STATUS_UNSPECIFIED
+}
+
+func (x *Location) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Location) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"` // created_at/updated_at игнорируются; status пустой => ACTIVE
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLocationRequest) Reset() {
+	*x = CreateLocationRequest{}
+	mi := &file_inventory_v1_location_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLocationRequest) ProtoMessage() {}
+
+func (x *CreateLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLocationRequest.ProtoReflect.Descriptor instead.
+func (*CreateLocationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateLocationRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type CreateLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLocationResponse) Reset() {
+	*x = CreateLocationResponse{}
+	mi := &file_inventory_v1_location_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLocationResponse) ProtoMessage() {}
+
+func (x *CreateLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLocationResponse.ProtoReflect.Descriptor instead.
+func (*CreateLocationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateLocationResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type GetLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationRequest) Reset() {
+	*x = GetLocationRequest{}
+	mi := &file_inventory_v1_location_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationRequest) ProtoMessage() {}
+
+func (x *GetLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationRequest.ProtoReflect.Descriptor instead.
+func (*GetLocationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLocationRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type GetLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationResponse) Reset() {
+	*x = GetLocationResponse{}
+	mi := &file_inventory_v1_location_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationResponse) ProtoMessage() {}
+
+func (x *GetLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationResponse.ProtoReflect.Descriptor instead.
+func (*GetLocationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetLocationResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type ListLocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        LocationStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=inventory.v1.LocationStatus" json:"status,omitempty"` // UNSPECIFIED — любые
+	Type          LocationType           `protobuf:"varint,2,opt,name=type,proto3,enum=inventory.v1.LocationType" json:"type,omitempty"`       // UNSPECIFIED — любые
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`                                       // точное совпадение
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationsRequest) Reset() {
+	*x = ListLocationsRequest{}
+	mi := &file_inventory_v1_location_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationsRequest) ProtoMessage() {}
+
+func (x *ListLocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationsRequest.ProtoReflect.Descriptor instead.
+func (*ListLocationsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLocationsRequest) GetStatus() LocationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return LocationStatus_LOCATION_STATUS_UNSPECIFIED
+}
+
+func (x *ListLocationsRequest) GetType() LocationType {
+	if x != nil {
+		return x.Type
+	}
+	return LocationType_LOCATION_TYPE_UNSPECIFIED
+}
+
+func (x *ListLocationsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type ListLocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*Location            `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"` // по возрастанию code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationsResponse) Reset() {
+	*x = ListLocationsResponse{}
+	mi := &file_inventory_v1_location_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationsResponse) ProtoMessage() {}
+
+func (x *ListLocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationsResponse.ProtoReflect.Descriptor instead.
+func (*ListLocationsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLocationsResponse) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+// Меняются только заданные поля.
+type UpdateLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	City          *string                `protobuf:"bytes,3,opt,name=city,proto3,oneof" json:"city,omitempty"`
+	Type          *LocationType          `protobuf:"varint,4,opt,name=type,proto3,enum=inventory.v1.LocationType,oneof" json:"type,omitempty"`
+	Status        *LocationStatus        `protobuf:"varint,5,opt,name=status,proto3,enum=inventory.v1.LocationStatus,oneof" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLocationRequest) Reset() {
+	*x = UpdateLocationRequest{}
+	mi := &file_inventory_v1_location_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLocationRequest) ProtoMessage() {}
+
+func (x *UpdateLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLocationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLocationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateLocationRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *UpdateLocationRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateLocationRequest) GetCity() string {
+	if x != nil && x.City != nil {
+		return *x.City
+	}
+	return ""
+}
+
+func (x *UpdateLocationRequest) GetType() LocationType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return LocationType_LOCATION_TYPE_UNSPECIFIED
+}
+
+func (x *UpdateLocationRequest) GetStatus() LocationStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return LocationStatus_LOCATION_STATUS_UNSPECIFIED
+}
+
+type UpdateLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLocationResponse) Reset() {
+	*x = UpdateLocationResponse{}
+	mi := &file_inventory_v1_location_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLocationResponse) ProtoMessage() {}
+
+func (x *UpdateLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLocationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLocationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateLocationResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+// Удалить можно только пустую локацию (on_hand = 0 и нет резервов); иначе FAILED_PRECONDITION —
+// такую локацию стоит перевести в INACTIVE.
+type DeleteLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLocationRequest) Reset() {
+	*x = DeleteLocationRequest{}
+	mi := &file_inventory_v1_location_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLocationRequest) ProtoMessage() {}
+
+func (x *DeleteLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLocationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLocationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteLocationRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DeleteLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLocationResponse) Reset() {
+	*x = DeleteLocationResponse{}
+	mi := &file_inventory_v1_location_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLocationResponse) ProtoMessage() {}
+
+func (x *DeleteLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_location_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLocationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLocationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_location_proto_rawDescGZIP(), []int{10}
+}
+
+var File_inventory_v1_location_proto protoreflect.FileDescriptor
+
+const file_inventory_v1_location_proto_rawDesc = "" +
+	"\n" +
+	"\x1binventory/v1/location.proto\x12\finventory.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x02\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12.\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x1a.inventory.v1.LocationTypeR\x04type\x124\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1c.inventory.v1.LocationStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"K\n" +
+	"\x15CreateLocationRequest\x122\n" +
+	"\blocation\x18\x01 \x01(\v2\x16.inventory.v1.LocationR\blocation\"L\n" +
+	"\x16CreateLocationResponse\x122\n" +
+	"\blocation\x18\x01 \x01(\v2\x16.inventory.v1.LocationR\blocation\"(\n" +
+	"\x12GetLocationRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"I\n" +
+	"\x13GetLocationResponse\x122\n" +
+	"\blocation\x18\x01 \x01(\v2\x16.inventory.v1.LocationR\blocation\"\x90\x01\n" +
+	"\x14ListLocationsRequest\x124\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x1c.inventory.v1.LocationStatusR\x06status\x12.\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1a.inventory.v1.LocationTypeR\x04type\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\"M\n" +
+	"\x15ListLocationsResponse\x124\n" +
+	"\tlocations\x18\x01 \x03(\v2\x16.inventory.v1.LocationR\tlocations\"\xf3\x01\n" +
+	"\x15UpdateLocationRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x17\n" +
+	"\x04city\x18\x03 \x01(\tH\x01R\x04city\x88\x01\x01\x123\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x1a.inventory.v1.LocationTypeH\x02R\x04type\x88\x01\x01\x129\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1c.inventory.v1.LocationStatusH\x03R\x06status\x88\x01\x01B\a\n" +
+	"\x05_nameB\a\n" +
+	"\x05_cityB\a\n" +
+	"\x05_typeB\t\n" +
+	"\a_status\"L\n" +
+	"\x16UpdateLocationResponse\x122\n" +
+	"\blocation\x18\x01 \x01(\v2\x16.inventory.v1.LocationR\blocation\"+\n" +
+	"\x15DeleteLocationRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x18\n" +
+	"\x16DeleteLocationResponse*\x83\x01\n" +
+	"\fLocationType\x12\x1d\n" +
+	"\x19LOCATION_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17LOCATION_TYPE_WAREHOUSE\x10\x01\x12\x17\n" +
+	"\x13LOCATION_TYPE_STORE\x10\x02\x12\x1e\n" +
+	"\x1aLOCATION_TYPE_PICKUP_POINT\x10\x03*k\n" +
+	"\x0eLocationStatus\x12\x1f\n" +
+	"\x1bLOCATION_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16LOCATION_STATUS_ACTIVE\x10\x01\x12\x1c\n" +
+	"\x18LOCATION_STATUS_INACTIVE\x10\x022\xdb\x03\n" +
+	"\x14LocationAdminService\x12[\n" +
+	"\x0eCreateLocation\x12#.inventory.v1.CreateLocationRequest\x1a$.inventory.v1.CreateLocationResponse\x12R\n" +
+	"\vGetLocation\x12 .inventory.v1.GetLocationRequest\x1a!.inventory.v1.GetLocationResponse\x12X\n" +
+	"\rListLocations\x12\".inventory.v1.ListLocationsRequest\x1a#.inventory.v1.ListLocationsResponse\x12[\n" +
+	"\x0eUpdateLocation\x12#.inventory.v1.UpdateLocationRequest\x1a$.inventory.v1.UpdateLocationResponse\x12[\n" +
+	"\x0eDeleteLocation\x12#.inventory.v1.DeleteLocationRequest\x1a$.inventory.v1.DeleteLocationResponseB7Z5github.com/YanMak/ecommerce/v2/gen/inventory/v1;invpbb\x06proto3"
+
+var (
+	file_inventory_v1_location_proto_rawDescOnce sync.Once
+	file_inventory_v1_location_proto_rawDescData []byte
+)
+
+func file_inventory_v1_location_proto_rawDescGZIP() []byte {
+	file_inventory_v1_location_proto_rawDescOnce.Do(func() {
+		file_inventory_v1_location_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_inventory_v1_location_proto_rawDesc), len(file_inventory_v1_location_proto_rawDesc)))
+	})
+	return file_inventory_v1_location_proto_rawDescData
+}
+
+var file_inventory_v1_location_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_inventory_v1_location_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_inventory_v1_location_proto_goTypes = []any{
+	(LocationType)(0),              // 0: inventory.v1.LocationType
+	(LocationStatus)(0),            // 1: inventory.v1.LocationStatus
+	(*Location)(nil),               // 2: inventory.v1.Location
+	(*CreateLocationRequest)(nil),  // 3: inventory.v1.CreateLocationRequest
+	(*CreateLocationResponse)(nil), // 4: inventory.v1.CreateLocationResponse
+	(*GetLocationRequest)(nil),     // 5: inventory.v1.GetLocationRequest
+	(*GetLocationResponse)(nil),    // 6: inventory.v1.GetLocationResponse
+	(*ListLocationsRequest)(nil),   // 7: inventory.v1.ListLocationsRequest
+	(*ListLocationsResponse)(nil),  // 8: inventory.v1.ListLocationsResponse
+	(*UpdateLocationRequest)(nil),  // 9: inventory.v1.UpdateLocationRequest
+	(*UpdateLocationResponse)(nil), // 10: inventory.v1.UpdateLocationResponse
+	(*DeleteLocationRequest)(nil),  // 11: inventory.v1.DeleteLocationRequest
+	(*DeleteLocationResponse)(nil), // 12: inventory.v1.DeleteLocationResponse
+	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
+}
+var file_inventory_v1_location_proto_depIdxs = []int32{
+	0,  // 0: inventory.v1.Location.type:type_name -> inventory.v1.LocationType
+	1,  // 1: inventory.v1.Location.status:type_name -> inventory.v1.LocationStatus
+	13, // 2: inventory.v1.Location.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: inventory.v1.Location.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 4: inventory.v1.CreateLocationRequest.location:type_name -> inventory.v1.Location
+	2,  // 5: inventory.v1.CreateLocationResponse.location:type_name -> inventory.v1.Location
+	2,  // 6: inventory.v1.GetLocationResponse.location:type_name -> inventory.v1.Location
+	1,  // 7: inventory.v1.ListLocationsRequest.status:type_name -> inventory.v1.LocationStatus
+	0,  // 8: inventory.v1.ListLocationsRequest.type:type_name -> inventory.v1.LocationType
+	2,  // 9: inventory.v1.ListLocationsResponse.locations:type_name -> inventory.v1.Location
+	0,  // 10: inventory.v1.UpdateLocationRequest.type:type_name -> inventory.v1.LocationType
+	1,  // 11: inventory.v1.UpdateLocationRequest.status:type_name -> inventory.v1.LocationStatus
+	2,  // 12: inventory.v1.UpdateLocationResponse.location:type_name -> inventory.v1.Location
+	3,  // 13: inventory.v1.LocationAdminService.CreateLocation:input_type -> inventory.v1.CreateLocationRequest
+	5,  // 14: inventory.v1.LocationAdminService.GetLocation:input_type -> inventory.v1.GetLocationRequest
+	7,  // 15: inventory.v1.LocationAdminService.ListLocations:input_type -> inventory.v1.ListLocationsRequest
+	9,  // 16: inventory.v1.LocationAdminService.UpdateLocation:input_type -> inventory.v1.UpdateLocationRequest
+	11, // 17: inventory.v1.LocationAdminService.DeleteLocation:input_type -> inventory.v1.DeleteLocationRequest
+	4,  // 18: inventory.v1.LocationAdminService.CreateLocation:output_type -> inventory.v1.CreateLocationResponse
+	6,  // 19: inventory.v1.LocationAdminService.GetLocation:output_type -> inventory.v1.GetLocationResponse
+	8,  // 20: inventory.v1.LocationAdminService.ListLocations:output_type -> inventory.v1.ListLocationsResponse
+	10, // 21: inventory.v1.LocationAdminService.UpdateLocation:output_type -> inventory.v1.UpdateLocationResponse
+	12, // 22: inventory.v1.LocationAdminService.DeleteLocation:output_type -> inventory.v1.DeleteLocationResponse
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_inventory_v1_location_proto_init() }
+func file_inventory_v1_location_proto_init() {
+	if File_inventory_v1_location_proto != nil {
+		return
+	}
+	file_inventory_v1_location_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_v1_location_proto_rawDesc), len(file_inventory_v1_location_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_inventory_v1_location_proto_goTypes,
+		DependencyIndexes: file_inventory_v1_location_proto_depIdxs,
+		EnumInfos:         file_inventory_v1_location_proto_enumTypes,
+		MessageInfos:      file_inventory_v1_location_proto_msgTypes,
+	}.Build()
+	File_inventory_v1_location_proto = out.File
+	file_inventory_v1_location_proto_goTypes = nil
+	file_inventory_v1_location_proto_depIdxs = nil
+}
@@ -9,6 +9,7 @@ package invpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,14 +23,121 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type BatchGetMode int32
+
+const (
+	BatchGetMode_BATCH_GET_MODE_UNSPECIFIED BatchGetMode = 0 // = STRICT
+	BatchGetMode_BATCH_GET_MODE_STRICT      BatchGetMode = 1 // нет хотя бы одного item_id — NOT_FOUND на весь запрос
+	BatchGetMode_BATCH_GET_MODE_PARTIAL     BatchGetMode = 2 // найденные — в stocks, остальные — в missing_item_ids
+)
+
+// Enum value maps for BatchGetMode.
+var (
+	BatchGetMode_name = map[int32]string{
+		0: "BATCH_GET_MODE_UNSPECIFIED",
+		1: "BATCH_GET_MODE_STRICT",
+		2: "BATCH_GET_MODE_PARTIAL",
+	}
+	BatchGetMode_value = map[string]int32{
+		"BATCH_GET_MODE_UNSPECIFIED": 0,
+		"BATCH_GET_MODE_STRICT":      1,
+		"BATCH_GET_MODE_PARTIAL":     2,
+	}
+)
+
+func (x BatchGetMode) Enum() *BatchGetMode {
+	p := new(BatchGetMode)
+	*p = x
+	return p
+}
+
+func (x BatchGetMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchGetMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_stock_proto_enumTypes[0].Descriptor()
+}
+
+func (BatchGetMode) Type() protoreflect.EnumType {
+	return &file_inventory_v1_stock_proto_enumTypes[0]
+}
+
+func (x BatchGetMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchGetMode.Descriptor instead.
+func (BatchGetMode) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{0}
+}
+
+type ReservationState int32
+
+const (
+	ReservationState_RESERVATION_STATE_UNSPECIFIED ReservationState = 0
+	ReservationState_RESERVATION_ACTIVE            ReservationState = 1 // держит остаток до expires_at
+	ReservationState_RESERVATION_COMMITTED         ReservationState = 2 // подтверждён: on_hand уменьшен на quantity
+	ReservationState_RESERVATION_RELEASED          ReservationState = 3 // отпущен вызывающей стороной
+	ReservationState_RESERVATION_EXPIRED           ReservationState = 4 // истёк TTL
+)
+
+// Enum value maps for ReservationState.
+var (
+	ReservationState_name = map[int32]string{
+		0: "RESERVATION_STATE_UNSPECIFIED",
+		1: "RESERVATION_ACTIVE",
+		2: "RESERVATION_COMMITTED",
+		3: "RESERVATION_RELEASED",
+		4: "RESERVATION_EXPIRED",
+	}
+	ReservationState_value = map[string]int32{
+		"RESERVATION_STATE_UNSPECIFIED": 0,
+		"RESERVATION_ACTIVE":            1,
+		"RESERVATION_COMMITTED":         2,
+		"RESERVATION_RELEASED":          3,
+		"RESERVATION_EXPIRED":           4,
+	}
+)
+
+func (x ReservationState) Enum() *ReservationState {
+	p := new(ReservationState)
+	*p = x
+	return p
+}
+
+func (x ReservationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReservationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_stock_proto_enumTypes[1].Descriptor()
+}
+
+func (ReservationState) Type() protoreflect.EnumType {
+	return &file_inventory_v1_stock_proto_enumTypes[1]
+}
+
+func (x ReservationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReservationState.Descriptor instead.
+func (ReservationState) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{1}
+}
+
 // Агрегированная модель остатков для item_id.
 // locations — деталь по складам (если не нужны — можно не заполнять).
 type Stock struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Available     int64                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"` // суммарный доступный остаток по всем локациям
+	Available     int64                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"` // суммарный доступный остаток по всем локациям (on_hand - reserved)
 	Locations     []*StockPerLocation    `protobuf:"bytes,3,rep,name=locations,proto3" json:"locations,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Reserved      int64                  `protobuf:"varint,5,opt,name=reserved,proto3" json:"reserved,omitempty"`                    // под активными резервами
+	OnHand        int64                  `protobuf:"varint,6,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`          // физически на складах
+	InTransit     int64                  `protobuf:"varint,7,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"` // едет между локациями (в available/on_hand не входит)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -92,11 +200,36 @@ func (x *Stock) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Stock) GetReserved() int64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *Stock) GetOnHand() int64 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *Stock) GetInTransit() int64 {
+	if x != nil {
+		return x.InTransit
+	}
+	return 0
+}
+
 type StockPerLocation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LocationCode  string                 `protobuf:"bytes,1,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // например "MSK-01"
+	LocationCode  string                 `protobuf:"bytes,1,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // например "MSK-01"; справочник — LocationAdminService
 	Available     int64                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // с точностью до наносекунд
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                     // растёт на 1 при каждом изменении локации (для expected_version)
+	Reserved      int64                  `protobuf:"varint,5,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	OnHand        int64                  `protobuf:"varint,6,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`
+	InTransit     int64                  `protobuf:"varint,7,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"` // едет В эту локацию (TransferStock с in_transit), ещё не принято
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -152,11 +285,44 @@ func (x *StockPerLocation) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *StockPerLocation) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StockPerLocation) GetReserved() int64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *StockPerLocation) GetOnHand() int64 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *StockPerLocation) GetInTransit() int64 {
+	if x != nil {
+		return x.InTransit
+	}
+	return 0
+}
+
 type GetStockRequest struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	ItemId int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
 	// Если указать location_code — вернём только её (и пересчёт available).
-	LocationCode  string `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	LocationCode string `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	// Остаток на момент времени (восстанавливается по журналу движений).
+	// В ответе on_hand/updated_at/version на as_of; reserved и in_transit историю не ведут
+	// и равны 0, поэтому available = on_hand. Локации, которых тогда ещё не было, не попадут в ответ;
+	// если у товара не было ни одной — NOT_FOUND.
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -205,6 +371,13 @@ func (x *GetStockRequest) GetLocationCode() string {
 	return ""
 }
 
+func (x *GetStockRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
 type GetStockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stock         *Stock                 `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"`
@@ -251,8 +424,10 @@ func (x *GetStockResponse) GetStock() *Stock {
 
 type BatchGetStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemIds       []int64                `protobuf:"varint,1,rep,packed,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`        // до N штук за раз
+	ItemIds       []int64                `protobuf:"varint,1,rep,packed,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`        // до GetLimitsResponse.max_batch_size штук за раз
 	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // опционально фильтровать по локации
+	Mode          BatchGetMode           `protobuf:"varint,3,opt,name=mode,proto3,enum=inventory.v1.BatchGetMode" json:"mode,omitempty"`
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"` // см. GetStockRequest.as_of; «не было» = отсутствует
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -301,11 +476,26 @@ func (x *BatchGetStockRequest) GetLocationCode() string {
 	return ""
 }
 
+func (x *BatchGetStockRequest) GetMode() BatchGetMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchGetMode_BATCH_GET_MODE_UNSPECIFIED
+}
+
+func (x *BatchGetStockRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
 type BatchGetStockResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stocks        []*Stock               `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"` // порядок тот же, что и в запросе
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Stocks         []*Stock               `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`                                                 // порядок тот же, что и в запросе (PARTIAL — без отсутствующих)
+	MissingItemIds []int64                `protobuf:"varint,2,rep,packed,name=missing_item_ids,json=missingItemIds,proto3" json:"missing_item_ids,omitempty"` // PARTIAL: неизвестные inventory, в порядке запроса, без повторов
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *BatchGetStockResponse) Reset() {
@@ -345,35 +535,728 @@ func (x *BatchGetStockResponse) GetStocks() []*Stock {
 	return nil
 }
 
-var File_inventory_v1_stock_proto protoreflect.FileDescriptor
+func (x *BatchGetStockResponse) GetMissingItemIds() []int64 {
+	if x != nil {
+		return x.MissingItemIds
+	}
+	return nil
+}
 
-const file_inventory_v1_stock_proto_rawDesc = "" +
-	"\n" +
-	"\x18inventory/v1/stock.proto\x12\finventory.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb7\x01\n" +
-	"\x05Stock\x12\x17\n" +
-	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12\x1c\n" +
-	"\tavailable\x18\x02 \x01(\x03R\tavailable\x12<\n" +
-	"\tlocations\x18\x03 \x03(\v2\x1e.inventory.v1.StockPerLocationR\tlocations\x129\n" +
-	"\n" +
-	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x90\x01\n" +
-	"\x10StockPerLocation\x12#\n" +
-	"\rlocation_code\x18\x01 \x01(\tR\flocationCode\x12\x1c\n" +
-	"\tavailable\x18\x02 \x01(\x03R\tavailable\x129\n" +
-	"\n" +
-	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"O\n" +
-	"\x0fGetStockRequest\x12\x17\n" +
-	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
-	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\"=\n" +
-	"\x10GetStockResponse\x12)\n" +
-	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"V\n" +
-	"\x14BatchGetStockRequest\x12\x19\n" +
-	"\bitem_ids\x18\x01 \x03(\x03R\aitemIds\x12#\n" +
-	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\"D\n" +
-	"\x15BatchGetStockResponse\x12+\n" +
-	"\x06stocks\x18\x01 \x03(\v2\x13.inventory.v1.StockR\x06stocks2\xb3\x01\n" +
-	"\fStockService\x12I\n" +
-	"\bGetStock\x12\x1d.inventory.v1.GetStockRequest\x1a\x1e.inventory.v1.GetStockResponse\x12X\n" +
-	"\rBatchGetStock\x12\".inventory.v1.BatchGetStockRequest\x1a#.inventory.v1.BatchGetStockResponseB%Z#ecommerce/v2/gen/inventory/v1;invpbb\x06proto3"
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	ItemId        int64                  `protobuf:"varint,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode  string                 `protobuf:"bytes,3,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	Quantity      int64                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	State         ReservationState       `protobuf:"varint,5,opt,name=state,proto3,enum=inventory.v1.ReservationState" json:"state,omitempty"`
+	Reference     string                 `protobuf:"bytes,6,opt,name=reference,proto3" json:"reference,omitempty"` // напр. id заказа/корзины
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Reservation) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+func (x *Reservation) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *Reservation) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *Reservation) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Reservation) GetState() ReservationState {
+	if x != nil {
+		return x.State
+	}
+	return ReservationState_RESERVATION_STATE_UNSPECIFIED
+}
+
+func (x *Reservation) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Reservation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // обязателен
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`                            // > 0
+	Ttl           *durationpb.Duration   `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`                                       // по умолчанию 15m, максимум 24h
+	Reference     string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReserveStockRequest) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReserveStockResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+func (x *ReserveStockResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CommitReservationRequest) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type CommitReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationResponse) Reset() {
+	*x = CommitReservationResponse{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationResponse) ProtoMessage() {}
+
+func (x *CommitReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationResponse.ProtoReflect.Descriptor instead.
+func (*CommitReservationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CommitReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+func (x *CommitReservationResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReleaseReservationRequest) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type ReleaseReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationResponse) Reset() {
+	*x = ReleaseReservationResponse{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationResponse) ProtoMessage() {}
+
+func (x *ReleaseReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReleaseReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+func (x *ReleaseReservationResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type WatchStockRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ItemIds      []int64                `protobuf:"varint,1,rep,packed,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`        // до max_batch_size штук, как в BatchGetStock
+	LocationCode string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // опционально: только изменения этой локации
+	// resume_token из последнего полученного StockEvent. Пусто — сначала придёт текущее
+	// состояние всех item_ids, затем изменения. Если токен устарел (рестарт сервера,
+	// вытесненная история) — сервер тоже пришлёт текущее состояние целиком.
+	ResumeToken   string `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStockRequest) Reset() {
+	*x = WatchStockRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStockRequest) ProtoMessage() {}
+
+func (x *WatchStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStockRequest.ProtoReflect.Descriptor instead.
+func (*WatchStockRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchStockRequest) GetItemIds() []int64 {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+func (x *WatchStockRequest) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *WatchStockRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type StockEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stock         *Stock                 `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"` // актуальное состояние товара (с учётом location_code)
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockEvent) Reset() {
+	*x = StockEvent{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockEvent) ProtoMessage() {}
+
+func (x *StockEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockEvent.ProtoReflect.Descriptor instead.
+func (*StockEvent) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StockEvent) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+func (x *StockEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type GetLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLimitsRequest) Reset() {
+	*x = GetLimitsRequest{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLimitsRequest) ProtoMessage() {}
+
+func (x *GetLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{15}
+}
+
+// Лимиты сервера (настраиваются на стороне inventory-svc): клиент режет запросы по ним.
+type GetLimitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxBatchSize  int32                  `protobuf:"varint,1,opt,name=max_batch_size,json=maxBatchSize,proto3" json:"max_batch_size,omitempty"` // item_ids в BatchGetStock/WatchStock, lines в BatchAdjustStock
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLimitsResponse) Reset() {
+	*x = GetLimitsResponse{}
+	mi := &file_inventory_v1_stock_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLimitsResponse) ProtoMessage() {}
+
+func (x *GetLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLimitsResponse.ProtoReflect.Descriptor instead.
+func (*GetLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetLimitsResponse) GetMaxBatchSize() int32 {
+	if x != nil {
+		return x.MaxBatchSize
+	}
+	return 0
+}
+
+var File_inventory_v1_stock_proto protoreflect.FileDescriptor
+
+const file_inventory_v1_stock_proto_rawDesc = "" +
+	"\n" +
+	"\x18inventory/v1/stock.proto\x12\finventory.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x02\n" +
+	"\x05Stock\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\x03R\tavailable\x12<\n" +
+	"\tlocations\x18\x03 \x03(\v2\x1e.inventory.v1.StockPerLocationR\tlocations\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\breserved\x18\x05 \x01(\x03R\breserved\x12\x17\n" +
+	"\aon_hand\x18\x06 \x01(\x03R\x06onHand\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\a \x01(\x03R\tinTransit\"\xfe\x01\n" +
+	"\x10StockPerLocation\x12#\n" +
+	"\rlocation_code\x18\x01 \x01(\tR\flocationCode\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\x03R\tavailable\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12\x1a\n" +
+	"\breserved\x18\x05 \x01(\x03R\breserved\x12\x17\n" +
+	"\aon_hand\x18\x06 \x01(\x03R\x06onHand\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\a \x01(\x03R\tinTransit\"\x80\x01\n" +
+	"\x0fGetStockRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12/\n" +
+	"\x05as_of\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"=\n" +
+	"\x10GetStockResponse\x12)\n" +
+	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"\xb7\x01\n" +
+	"\x14BatchGetStockRequest\x12\x19\n" +
+	"\bitem_ids\x18\x01 \x03(\x03R\aitemIds\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12.\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x1a.inventory.v1.BatchGetModeR\x04mode\x12/\n" +
+	"\x05as_of\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"n\n" +
+	"\x15BatchGetStockResponse\x12+\n" +
+	"\x06stocks\x18\x01 \x03(\v2\x13.inventory.v1.StockR\x06stocks\x12(\n" +
+	"\x10missing_item_ids\x18\x02 \x03(\x03R\x0emissingItemIds\"\xd8\x02\n" +
+	"\vReservation\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x03 \x01(\tR\flocationCode\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x03R\bquantity\x124\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x1e.inventory.v1.ReservationStateR\x05state\x12\x1c\n" +
+	"\treference\x18\x06 \x01(\tR\treference\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xba\x01\n" +
+	"\x13ReserveStockRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12+\n" +
+	"\x03ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1c\n" +
+	"\treference\x18\x05 \x01(\tR\treference\"~\n" +
+	"\x14ReserveStockResponse\x12;\n" +
+	"\vreservation\x18\x01 \x01(\v2\x19.inventory.v1.ReservationR\vreservation\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\"A\n" +
+	"\x18CommitReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\"\x83\x01\n" +
+	"\x19CommitReservationResponse\x12;\n" +
+	"\vreservation\x18\x01 \x01(\v2\x19.inventory.v1.ReservationR\vreservation\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\"B\n" +
+	"\x19ReleaseReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\"\x84\x01\n" +
+	"\x1aReleaseReservationResponse\x12;\n" +
+	"\vreservation\x18\x01 \x01(\v2\x19.inventory.v1.ReservationR\vreservation\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\"v\n" +
+	"\x11WatchStockRequest\x12\x19\n" +
+	"\bitem_ids\x18\x01 \x03(\x03R\aitemIds\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12!\n" +
+	"\fresume_token\x18\x03 \x01(\tR\vresumeToken\"Z\n" +
+	"\n" +
+	"StockEvent\x12)\n" +
+	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"\x12\n" +
+	"\x10GetLimitsRequest\"9\n" +
+	"\x11GetLimitsResponse\x12$\n" +
+	"\x0emax_batch_size\x18\x01 \x01(\x05R\fmaxBatchSize*e\n" +
+	"\fBatchGetMode\x12\x1e\n" +
+	"\x1aBATCH_GET_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15BATCH_GET_MODE_STRICT\x10\x01\x12\x1a\n" +
+	"\x16BATCH_GET_MODE_PARTIAL\x10\x02*\x9b\x01\n" +
+	"\x10ReservationState\x12!\n" +
+	"\x1dRESERVATION_STATE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RESERVATION_ACTIVE\x10\x01\x12\x19\n" +
+	"\x15RESERVATION_COMMITTED\x10\x02\x12\x18\n" +
+	"\x14RESERVATION_RELEASED\x10\x03\x12\x17\n" +
+	"\x13RESERVATION_EXPIRED\x10\x042\xf2\x04\n" +
+	"\fStockService\x12I\n" +
+	"\bGetStock\x12\x1d.inventory.v1.GetStockRequest\x1a\x1e.inventory.v1.GetStockResponse\x12X\n" +
+	"\rBatchGetStock\x12\".inventory.v1.BatchGetStockRequest\x1a#.inventory.v1.BatchGetStockResponse\x12I\n" +
+	"\n" +
+	"WatchStock\x12\x1f.inventory.v1.WatchStockRequest\x1a\x18.inventory.v1.StockEvent0\x01\x12U\n" +
+	"\fReserveStock\x12!.inventory.v1.ReserveStockRequest\x1a\".inventory.v1.ReserveStockResponse\x12d\n" +
+	"\x11CommitReservation\x12&.inventory.v1.CommitReservationRequest\x1a'.inventory.v1.CommitReservationResponse\x12g\n" +
+	"\x12ReleaseReservation\x12'.inventory.v1.ReleaseReservationRequest\x1a(.inventory.v1.ReleaseReservationResponse\x12L\n" +
+	"\tGetLimits\x12\x1e.inventory.v1.GetLimitsRequest\x1a\x1f.inventory.v1.GetLimitsResponseB7Z5github.com/YanMak/ecommerce/v2/gen/inventory/v1;invpbb\x06proto3"
 
 var (
 	file_inventory_v1_stock_proto_rawDescOnce sync.Once
@@ -387,31 +1270,70 @@ func file_inventory_v1_stock_proto_rawDescGZIP() []byte {
 	return file_inventory_v1_stock_proto_rawDescData
 }
 
-var file_inventory_v1_stock_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_inventory_v1_stock_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_inventory_v1_stock_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_inventory_v1_stock_proto_goTypes = []any{
-	(*Stock)(nil),                 // 0: inventory.v1.Stock
-	(*StockPerLocation)(nil),      // 1: inventory.v1.StockPerLocation
-	(*GetStockRequest)(nil),       // 2: inventory.v1.GetStockRequest
-	(*GetStockResponse)(nil),      // 3: inventory.v1.GetStockResponse
-	(*BatchGetStockRequest)(nil),  // 4: inventory.v1.BatchGetStockRequest
-	(*BatchGetStockResponse)(nil), // 5: inventory.v1.BatchGetStockResponse
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(BatchGetMode)(0),                  // 0: inventory.v1.BatchGetMode
+	(ReservationState)(0),              // 1: inventory.v1.ReservationState
+	(*Stock)(nil),                      // 2: inventory.v1.Stock
+	(*StockPerLocation)(nil),           // 3: inventory.v1.StockPerLocation
+	(*GetStockRequest)(nil),            // 4: inventory.v1.GetStockRequest
+	(*GetStockResponse)(nil),           // 5: inventory.v1.GetStockResponse
+	(*BatchGetStockRequest)(nil),       // 6: inventory.v1.BatchGetStockRequest
+	(*BatchGetStockResponse)(nil),      // 7: inventory.v1.BatchGetStockResponse
+	(*Reservation)(nil),                // 8: inventory.v1.Reservation
+	(*ReserveStockRequest)(nil),        // 9: inventory.v1.ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 10: inventory.v1.ReserveStockResponse
+	(*CommitReservationRequest)(nil),   // 11: inventory.v1.CommitReservationRequest
+	(*CommitReservationResponse)(nil),  // 12: inventory.v1.CommitReservationResponse
+	(*ReleaseReservationRequest)(nil),  // 13: inventory.v1.ReleaseReservationRequest
+	(*ReleaseReservationResponse)(nil), // 14: inventory.v1.ReleaseReservationResponse
+	(*WatchStockRequest)(nil),          // 15: inventory.v1.WatchStockRequest
+	(*StockEvent)(nil),                 // 16: inventory.v1.StockEvent
+	(*GetLimitsRequest)(nil),           // 17: inventory.v1.GetLimitsRequest
+	(*GetLimitsResponse)(nil),          // 18: inventory.v1.GetLimitsResponse
+	(*timestamppb.Timestamp)(nil),      // 19: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),        // 20: google.protobuf.Duration
 }
 var file_inventory_v1_stock_proto_depIdxs = []int32{
-	1, // 0: inventory.v1.Stock.locations:type_name -> inventory.v1.StockPerLocation
-	6, // 1: inventory.v1.Stock.updated_at:type_name -> google.protobuf.Timestamp
-	6, // 2: inventory.v1.StockPerLocation.updated_at:type_name -> google.protobuf.Timestamp
-	0, // 3: inventory.v1.GetStockResponse.stock:type_name -> inventory.v1.Stock
-	0, // 4: inventory.v1.BatchGetStockResponse.stocks:type_name -> inventory.v1.Stock
-	2, // 5: inventory.v1.StockService.GetStock:input_type -> inventory.v1.GetStockRequest
-	4, // 6: inventory.v1.StockService.BatchGetStock:input_type -> inventory.v1.BatchGetStockRequest
-	3, // 7: inventory.v1.StockService.GetStock:output_type -> inventory.v1.GetStockResponse
-	5, // 8: inventory.v1.StockService.BatchGetStock:output_type -> inventory.v1.BatchGetStockResponse
-	7, // [7:9] is the sub-list for method output_type
-	5, // [5:7] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	3,  // 0: inventory.v1.Stock.locations:type_name -> inventory.v1.StockPerLocation
+	19, // 1: inventory.v1.Stock.updated_at:type_name -> google.protobuf.Timestamp
+	19, // 2: inventory.v1.StockPerLocation.updated_at:type_name -> google.protobuf.Timestamp
+	19, // 3: inventory.v1.GetStockRequest.as_of:type_name -> google.protobuf.Timestamp
+	2,  // 4: inventory.v1.GetStockResponse.stock:type_name -> inventory.v1.Stock
+	0,  // 5: inventory.v1.BatchGetStockRequest.mode:type_name -> inventory.v1.BatchGetMode
+	19, // 6: inventory.v1.BatchGetStockRequest.as_of:type_name -> google.protobuf.Timestamp
+	2,  // 7: inventory.v1.BatchGetStockResponse.stocks:type_name -> inventory.v1.Stock
+	1,  // 8: inventory.v1.Reservation.state:type_name -> inventory.v1.ReservationState
+	19, // 9: inventory.v1.Reservation.created_at:type_name -> google.protobuf.Timestamp
+	19, // 10: inventory.v1.Reservation.expires_at:type_name -> google.protobuf.Timestamp
+	20, // 11: inventory.v1.ReserveStockRequest.ttl:type_name -> google.protobuf.Duration
+	8,  // 12: inventory.v1.ReserveStockResponse.reservation:type_name -> inventory.v1.Reservation
+	2,  // 13: inventory.v1.ReserveStockResponse.stock:type_name -> inventory.v1.Stock
+	8,  // 14: inventory.v1.CommitReservationResponse.reservation:type_name -> inventory.v1.Reservation
+	2,  // 15: inventory.v1.CommitReservationResponse.stock:type_name -> inventory.v1.Stock
+	8,  // 16: inventory.v1.ReleaseReservationResponse.reservation:type_name -> inventory.v1.Reservation
+	2,  // 17: inventory.v1.ReleaseReservationResponse.stock:type_name -> inventory.v1.Stock
+	2,  // 18: inventory.v1.StockEvent.stock:type_name -> inventory.v1.Stock
+	4,  // 19: inventory.v1.StockService.GetStock:input_type -> inventory.v1.GetStockRequest
+	6,  // 20: inventory.v1.StockService.BatchGetStock:input_type -> inventory.v1.BatchGetStockRequest
+	15, // 21: inventory.v1.StockService.WatchStock:input_type -> inventory.v1.WatchStockRequest
+	9,  // 22: inventory.v1.StockService.ReserveStock:input_type -> inventory.v1.ReserveStockRequest
+	11, // 23: inventory.v1.StockService.CommitReservation:input_type -> inventory.v1.CommitReservationRequest
+	13, // 24: inventory.v1.StockService.ReleaseReservation:input_type -> inventory.v1.ReleaseReservationRequest
+	17, // 25: inventory.v1.StockService.GetLimits:input_type -> inventory.v1.GetLimitsRequest
+	5,  // 26: inventory.v1.StockService.GetStock:output_type -> inventory.v1.GetStockResponse
+	7,  // 27: inventory.v1.StockService.BatchGetStock:output_type -> inventory.v1.BatchGetStockResponse
+	16, // 28: inventory.v1.StockService.WatchStock:output_type -> inventory.v1.StockEvent
+	10, // 29: inventory.v1.StockService.ReserveStock:output_type -> inventory.v1.ReserveStockResponse
+	12, // 30: inventory.v1.StockService.CommitReservation:output_type -> inventory.v1.CommitReservationResponse
+	14, // 31: inventory.v1.StockService.ReleaseReservation:output_type -> inventory.v1.ReleaseReservationResponse
+	18, // 32: inventory.v1.StockService.GetLimits:output_type -> inventory.v1.GetLimitsResponse
+	26, // [26:33] is the sub-list for method output_type
+	19, // [19:26] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_inventory_v1_stock_proto_init() }
@@ -424,13 +1346,14 @@ func file_inventory_v1_stock_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_v1_stock_proto_rawDesc), len(file_inventory_v1_stock_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_inventory_v1_stock_proto_goTypes,
 		DependencyIndexes: file_inventory_v1_stock_proto_depIdxs,
+		EnumInfos:         file_inventory_v1_stock_proto_enumTypes,
 		MessageInfos:      file_inventory_v1_stock_proto_msgTypes,
 	}.Build()
 	File_inventory_v1_stock_proto = out.File
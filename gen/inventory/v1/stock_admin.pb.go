@@ -31,6 +31,8 @@ const (
 	StockChangeReason_STOCK_CHANGE_CORRECTION         StockChangeReason = 2 // инвентаризация/коррекция
 	StockChangeReason_STOCK_CHANGE_RETURN             StockChangeReason = 3 // клиентский возврат
 	StockChangeReason_STOCK_CHANGE_MANUAL             StockChangeReason = 4 // ручная операция
+	StockChangeReason_STOCK_CHANGE_SALE               StockChangeReason = 5 // списание по подтверждённому резерву (CommitReservation)
+	StockChangeReason_STOCK_CHANGE_TRANSFER           StockChangeReason = 6 // перемещение между локациями (TransferStock)
 )
 
 // Enum value maps for StockChangeReason.
@@ -41,6 +43,8 @@ var (
 		2: "STOCK_CHANGE_CORRECTION",
 		3: "STOCK_CHANGE_RETURN",
 		4: "STOCK_CHANGE_MANUAL",
+		5: "STOCK_CHANGE_SALE",
+		6: "STOCK_CHANGE_TRANSFER",
 	}
 	StockChangeReason_value = map[string]int32{
 		"STOCK_CHANGE_REASON_UNSPECIFIED": 0,
@@ -48,6 +52,8 @@ var (
 		"STOCK_CHANGE_CORRECTION":         2,
 		"STOCK_CHANGE_RETURN":             3,
 		"STOCK_CHANGE_MANUAL":             4,
+		"STOCK_CHANGE_SALE":               5,
+		"STOCK_CHANGE_TRANSFER":           6,
 	}
 )
 
@@ -78,18 +84,126 @@ func (StockChangeReason) EnumDescriptor() ([]byte, []int) {
 	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{0}
 }
 
+type BatchMode int32
+
+const (
+	BatchMode_BATCH_MODE_UNSPECIFIED BatchMode = 0 // = ALL_OR_NOTHING
+	// Ошибка любой строки отменяет весь батч; ответ — ошибка RPC с "lines[i]" в сообщении
+	// (у нарушений по полям — путь "lines.i.<field>" в BadRequest).
+	BatchMode_BATCH_MODE_ALL_OR_NOTHING BatchMode = 1
+	// Применяются все строки, которые прошли проверки (одной записью журнала), упавшие
+	// пропускаются. RPC успешен, итог каждой строки — в results. Строки проверяются по порядку
+	// с учётом уже применённых строк этого же батча.
+	BatchMode_BATCH_MODE_BEST_EFFORT BatchMode = 2
+)
+
+// Enum value maps for BatchMode.
+var (
+	BatchMode_name = map[int32]string{
+		0: "BATCH_MODE_UNSPECIFIED",
+		1: "BATCH_MODE_ALL_OR_NOTHING",
+		2: "BATCH_MODE_BEST_EFFORT",
+	}
+	BatchMode_value = map[string]int32{
+		"BATCH_MODE_UNSPECIFIED":    0,
+		"BATCH_MODE_ALL_OR_NOTHING": 1,
+		"BATCH_MODE_BEST_EFFORT":    2,
+	}
+)
+
+func (x BatchMode) Enum() *BatchMode {
+	p := new(BatchMode)
+	*p = x
+	return p
+}
+
+func (x BatchMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_stock_admin_proto_enumTypes[1].Descriptor()
+}
+
+func (BatchMode) Type() protoreflect.EnumType {
+	return &file_inventory_v1_stock_admin_proto_enumTypes[1]
+}
+
+func (x BatchMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchMode.Descriptor instead.
+func (BatchMode) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{1}
+}
+
+// --- Перемещение между локациями ---
+type TransferState int32
+
+const (
+	TransferState_TRANSFER_STATE_UNSPECIFIED TransferState = 0
+	TransferState_TRANSFER_STATE_IN_TRANSIT  TransferState = 1 // источник списан, получатель ещё не принял
+	TransferState_TRANSFER_STATE_RECEIVED    TransferState = 2 // получатель оприходовал
+	TransferState_TRANSFER_STATE_CANCELLED   TransferState = 3 // отменено в пути, товар вернулся в источник
+)
+
+// Enum value maps for TransferState.
+var (
+	TransferState_name = map[int32]string{
+		0: "TRANSFER_STATE_UNSPECIFIED",
+		1: "TRANSFER_STATE_IN_TRANSIT",
+		2: "TRANSFER_STATE_RECEIVED",
+		3: "TRANSFER_STATE_CANCELLED",
+	}
+	TransferState_value = map[string]int32{
+		"TRANSFER_STATE_UNSPECIFIED": 0,
+		"TRANSFER_STATE_IN_TRANSIT":  1,
+		"TRANSFER_STATE_RECEIVED":    2,
+		"TRANSFER_STATE_CANCELLED":   3,
+	}
+)
+
+func (x TransferState) Enum() *TransferState {
+	p := new(TransferState)
+	*p = x
+	return p
+}
+
+func (x TransferState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransferState) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_v1_stock_admin_proto_enumTypes[2].Descriptor()
+}
+
+func (TransferState) Type() protoreflect.EnumType {
+	return &file_inventory_v1_stock_admin_proto_enumTypes[2]
+}
+
+func (x TransferState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransferState.Descriptor instead.
+func (TransferState) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{2}
+}
+
 // --- Adjust: инкремент/декремент по конкретной локации ---
 type AdjustStockRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
-	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"` // может быть <0 или >0, != 0
-	Reason        StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
-	Reference     string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
-	AllowNegative bool                   `protobuf:"varint,6,opt,name=allow_negative,json=allowNegative,proto3" json:"allow_negative,omitempty"`  // по умолчанию false
-	PrevUpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"` // оптимистическая блокировка по локации
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ItemId          int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode    string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"` // зарегистрированная ACTIVE-локация (см. location.proto)
+	Delta           int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`                                  // может быть <0 или >0, != 0
+	Reason          StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
+	Reference       string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	AllowNegative   bool                   `protobuf:"varint,6,opt,name=allow_negative,json=allowNegative,proto3" json:"allow_negative,omitempty"`       // по умолчанию false
+	PrevUpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"`      // оптимистическая блокировка по локации
+	ExpectedVersion int64                  `protobuf:"varint,8,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // альтернатива prev_updated_at; 0 — не проверять
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *AdjustStockRequest) Reset() {
@@ -171,6 +285,13 @@ func (x *AdjustStockRequest) GetPrevUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *AdjustStockRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type AdjustStockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stock         *Stock                 `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"` // используем Stock из stock.proto
@@ -217,15 +338,16 @@ func (x *AdjustStockResponse) GetStock() *Stock {
 
 // --- Set: задать точное значение по локации ---
 type SetStockRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
-	NewAvailable  int64                  `protobuf:"varint,3,opt,name=new_available,json=newAvailable,proto3" json:"new_available,omitempty"` // >= 0 (если нужно — разрешим <0 через флаг)
-	Reason        StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
-	Reference     string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
-	PrevUpdatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"` // Идемпотентность: metadata "idempotency-key: <uuid>"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ItemId          int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode    string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`  // зарегистрированная ACTIVE-локация
+	NewAvailable    int64                  `protobuf:"varint,3,opt,name=new_available,json=newAvailable,proto3" json:"new_available,omitempty"` // >= 0 и не ниже reserved: иначе FAILED_PRECONDITION, reason "BELOW_RESERVED"
+	Reason          StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
+	Reference       string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	PrevUpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,7,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // 0 — не проверять
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SetStockRequest) Reset() {
@@ -300,6 +422,13 @@ func (x *SetStockRequest) GetPrevUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *SetStockRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type SetStockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stock         *Stock                 `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"` // используем Stock из stock.proto
@@ -346,16 +475,17 @@ func (x *SetStockResponse) GetStock() *Stock {
 
 // --- Batch Adjust ---
 type BatchAdjustLine struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
-	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
-	Reason        StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
-	Reference     string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
-	AllowNegative bool                   `protobuf:"varint,6,opt,name=allow_negative,json=allowNegative,proto3" json:"allow_negative,omitempty"`
-	PrevUpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"` // можно не заполнять
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ItemId          int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode    string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	Delta           int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Reason          StockChangeReason      `protobuf:"varint,4,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
+	Reference       string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"`
+	AllowNegative   bool                   `protobuf:"varint,6,opt,name=allow_negative,json=allowNegative,proto3" json:"allow_negative,omitempty"`
+	PrevUpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=prev_updated_at,json=prevUpdatedAt,proto3" json:"prev_updated_at,omitempty"`      // можно не заполнять
+	ExpectedVersion int64                  `protobuf:"varint,8,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // можно не заполнять
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *BatchAdjustLine) Reset() {
@@ -437,9 +567,18 @@ func (x *BatchAdjustLine) GetPrevUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *BatchAdjustLine) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type BatchAdjustStockRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Lines         []*BatchAdjustLine     `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"` // сервер ограничит размер, напр., до 500
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Lines []*BatchAdjustLine     `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"` // сервер ограничит размер, напр., до 500
+	// Идемпотентность на весь батч — через metadata "idempotency-key"
+	Mode          BatchMode `protobuf:"varint,2,opt,name=mode,proto3,enum=inventory.v1.BatchMode" json:"mode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -481,16 +620,231 @@ func (x *BatchAdjustStockRequest) GetLines() []*BatchAdjustLine {
 	return nil
 }
 
-type BatchAdjustStockResponse struct {
+func (x *BatchAdjustStockRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_BATCH_MODE_UNSPECIFIED
+}
+
+// Ошибка одной строки — то же, что вернул бы AdjustStock для неё, но без провала всего RPC.
+type LineError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"` // google.rpc.Code: 3 INVALID_ARGUMENT, 9 FAILED_PRECONDITION, 10 ABORTED...
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`      // машинный код errorsx (как reason в ErrorInfo RPC): "VERSION_CONFLICT", "VALIDATION_FAILED", "NOT_FOUND"...
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Violations    []*FieldViolation      `protobuf:"bytes,4,rep,name=violations,proto3" json:"violations,omitempty"` // для VALIDATION_FAILED; field — внутри строки ("location_code")
+	Current       *Stock                 `protobuf:"bytes,5,opt,name=current,proto3" json:"current,omitempty"`       // для VERSION_CONFLICT: актуальное состояние товара
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineError) Reset() {
+	*x = LineError{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineError) ProtoMessage() {}
+
+func (x *LineError) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineError.ProtoReflect.Descriptor instead.
+func (*LineError) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LineError) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *LineError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *LineError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LineError) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *LineError) GetCurrent() *Stock {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // "LOCATION_UNKNOWN", "REQUIRED", ...
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Params        map[string]string      `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // параметры нарушения: {"max": "64"}
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type BatchAdjustLineResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stocks        []*Stock               `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"` // используем Stock из stock.proto
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // номер строки в запросе
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`  // строка применена: состояние товара после батча
+	Error         *LineError             `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`  // строка не применена
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAdjustLineResult) Reset() {
+	*x = BatchAdjustLineResult{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAdjustLineResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAdjustLineResult) ProtoMessage() {}
+
+func (x *BatchAdjustLineResult) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAdjustLineResult.ProtoReflect.Descriptor instead.
+func (*BatchAdjustLineResult) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchAdjustLineResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchAdjustLineResult) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+func (x *BatchAdjustLineResult) GetError() *LineError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type BatchAdjustStockResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Stocks        []*Stock                 `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`    // ALL_OR_NOTHING: по строкам запроса
+	Results       []*BatchAdjustLineResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`  // BEST_EFFORT: по строкам запроса
+	Applied       int32                    `protobuf:"varint,3,opt,name=applied,proto3" json:"applied,omitempty"` // BEST_EFFORT: сколько строк применено
+	Failed        int32                    `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BatchAdjustStockResponse) Reset() {
 	*x = BatchAdjustStockResponse{}
-	mi := &file_inventory_v1_stock_admin_proto_msgTypes[6]
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -502,7 +856,7 @@ func (x *BatchAdjustStockResponse) String() string {
 func (*BatchAdjustStockResponse) ProtoMessage() {}
 
 func (x *BatchAdjustStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_v1_stock_admin_proto_msgTypes[6]
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -515,7 +869,7 @@ func (x *BatchAdjustStockResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchAdjustStockResponse.ProtoReflect.Descriptor instead.
 func (*BatchAdjustStockResponse) Descriptor() ([]byte, []int) {
-	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{6}
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *BatchAdjustStockResponse) GetStocks() []*Stock {
@@ -525,52 +879,896 @@ func (x *BatchAdjustStockResponse) GetStocks() []*Stock {
 	return nil
 }
 
-var File_inventory_v1_stock_admin_proto protoreflect.FileDescriptor
+func (x *BatchAdjustStockResponse) GetResults() []*BatchAdjustLineResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
 
-const file_inventory_v1_stock_admin_proto_rawDesc = "" +
-	"\n" +
-	"\x1einventory/v1/stock_admin.proto\x12\finventory.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18inventory/v1/stock.proto\"\xaa\x02\n" +
-	"\x12AdjustStockRequest\x12\x17\n" +
-	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
-	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12\x14\n" +
-	"\x05delta\x18\x03 \x01(\x03R\x05delta\x127\n" +
-	"\x06reason\x18\x04 \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
-	"\treference\x18\x05 \x01(\tR\treference\x12%\n" +
-	"\x0eallow_negative\x18\x06 \x01(\bR\rallowNegative\x12B\n" +
-	"\x0fprev_updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\"@\n" +
-	"\x13AdjustStockResponse\x12)\n" +
-	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"\x8f\x02\n" +
-	"\x0fSetStockRequest\x12\x17\n" +
-	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
-	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12#\n" +
-	"\rnew_available\x18\x03 \x01(\x03R\fnewAvailable\x127\n" +
-	"\x06reason\x18\x04 \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
-	"\treference\x18\x05 \x01(\tR\treference\x12B\n" +
-	"\x0fprev_updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\"=\n" +
-	"\x10SetStockResponse\x12)\n" +
-	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"\xa7\x02\n" +
-	"\x0fBatchAdjustLine\x12\x17\n" +
-	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
-	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12\x14\n" +
-	"\x05delta\x18\x03 \x01(\x03R\x05delta\x127\n" +
+func (x *BatchAdjustStockResponse) GetApplied() int32 {
+	if x != nil {
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *BatchAdjustStockResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// --- Журнал движений (аудит: почему изменилось число) ---
+type StockMovement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // монотонно растёт
+	ItemId        int64                  `protobuf:"varint,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode  string                 `protobuf:"bytes,3,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	Delta         int64                  `protobuf:"varint,4,opt,name=delta,proto3" json:"delta,omitempty"` // after - before
+	Before        int64                  `protobuf:"varint,5,opt,name=before,proto3" json:"before,omitempty"`
+	After         int64                  `protobuf:"varint,6,opt,name=after,proto3" json:"after,omitempty"`
+	Reason        StockChangeReason      `protobuf:"varint,7,opt,name=reason,proto3,enum=inventory.v1.StockChangeReason" json:"reason,omitempty"`
+	Reference     string                 `protobuf:"bytes,8,opt,name=reference,proto3" json:"reference,omitempty"`
+	Actor         string                 `protobuf:"bytes,9,opt,name=actor,proto3" json:"actor,omitempty"` // из metadata "x-actor" (кто изменил)
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockMovement) Reset() {
+	*x = StockMovement{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockMovement) ProtoMessage() {}
+
+func (x *StockMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockMovement.ProtoReflect.Descriptor instead.
+func (*StockMovement) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StockMovement) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StockMovement) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *StockMovement) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *StockMovement) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *StockMovement) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *StockMovement) GetAfter() int64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *StockMovement) GetReason() StockChangeReason {
+	if x != nil {
+		return x.Reason
+	}
+	return StockChangeReason_STOCK_CHANGE_REASON_UNSPECIFIED
+}
+
+func (x *StockMovement) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *StockMovement) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StockMovement) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListStockMovementsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Фильтры (пустые — не фильтровать):
+	ItemId        int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	LocationCode  string                 `protobuf:"bytes,2,opt,name=location_code,json=locationCode,proto3" json:"location_code,omitempty"`
+	Reasons       []StockChangeReason    `protobuf:"varint,3,rep,packed,name=reasons,proto3,enum=inventory.v1.StockChangeReason" json:"reasons,omitempty"`
+	Reference     string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`                          // включительно
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`                          // не включительно
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // по умолчанию 100, максимум 1000
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // из next_page_token предыдущего ответа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockMovementsRequest) Reset() {
+	*x = ListStockMovementsRequest{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockMovementsRequest) ProtoMessage() {}
+
+func (x *ListStockMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockMovementsRequest.ProtoReflect.Descriptor instead.
+func (*ListStockMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListStockMovementsRequest) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *ListStockMovementsRequest) GetLocationCode() string {
+	if x != nil {
+		return x.LocationCode
+	}
+	return ""
+}
+
+func (x *ListStockMovementsRequest) GetReasons() []StockChangeReason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ListStockMovementsRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *ListStockMovementsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListStockMovementsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ListStockMovementsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListStockMovementsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListStockMovementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movements     []*StockMovement       `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`                                // от новых к старым
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пусто — страниц больше нет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStockMovementsResponse) Reset() {
+	*x = ListStockMovementsResponse{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStockMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStockMovementsResponse) ProtoMessage() {}
+
+func (x *ListStockMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStockMovementsResponse.ProtoReflect.Descriptor instead.
+func (*ListStockMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListStockMovementsResponse) GetMovements() []*StockMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+func (x *ListStockMovementsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Transfer struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TransferId       string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	ItemId           int64                  `protobuf:"varint,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	FromLocationCode string                 `protobuf:"bytes,3,opt,name=from_location_code,json=fromLocationCode,proto3" json:"from_location_code,omitempty"`
+	ToLocationCode   string                 `protobuf:"bytes,4,opt,name=to_location_code,json=toLocationCode,proto3" json:"to_location_code,omitempty"`
+	Quantity         int64                  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	State            TransferState          `protobuf:"varint,6,opt,name=state,proto3,enum=inventory.v1.TransferState" json:"state,omitempty"`
+	Reference        string                 `protobuf:"bytes,7,opt,name=reference,proto3" json:"reference,omitempty"` // общий для движений обеих сторон
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Transfer) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *Transfer) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *Transfer) GetFromLocationCode() string {
+	if x != nil {
+		return x.FromLocationCode
+	}
+	return ""
+}
+
+func (x *Transfer) GetToLocationCode() string {
+	if x != nil {
+		return x.ToLocationCode
+	}
+	return ""
+}
+
+func (x *Transfer) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Transfer) GetState() TransferState {
+	if x != nil {
+		return x.State
+	}
+	return TransferState_TRANSFER_STATE_UNSPECIFIED
+}
+
+func (x *Transfer) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Transfer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Transfer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Обе стороны меняются одной записью журнала; движения (STOCK_CHANGE_TRANSFER) пишутся
+// под одним reference (если пусто — transfer_id).
+type TransferStockRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ItemId           int64                  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	FromLocationCode string                 `protobuf:"bytes,2,opt,name=from_location_code,json=fromLocationCode,proto3" json:"from_location_code,omitempty"` // зарегистрированные ACTIVE-локации, from != to
+	ToLocationCode   string                 `protobuf:"bytes,3,opt,name=to_location_code,json=toLocationCode,proto3" json:"to_location_code,omitempty"`
+	Quantity         int64                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`  // > 0, не больше available источника
+	Reference        string                 `protobuf:"bytes,5,opt,name=reference,proto3" json:"reference,omitempty"` // номер накладной и т.п.
+	// false — сразу на получателе (RECEIVED).
+	// true  — только отгрузка: товар в in_transit получателя до ReceiveTransfer/CancelTransfer.
+	InTransit           bool  `protobuf:"varint,6,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"`
+	ExpectedFromVersion int64 `protobuf:"varint,7,opt,name=expected_from_version,json=expectedFromVersion,proto3" json:"expected_from_version,omitempty"` // опционально: оптимистическая блокировка источника
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TransferStockRequest) Reset() {
+	*x = TransferStockRequest{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferStockRequest) ProtoMessage() {}
+
+func (x *TransferStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferStockRequest.ProtoReflect.Descriptor instead.
+func (*TransferStockRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TransferStockRequest) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *TransferStockRequest) GetFromLocationCode() string {
+	if x != nil {
+		return x.FromLocationCode
+	}
+	return ""
+}
+
+func (x *TransferStockRequest) GetToLocationCode() string {
+	if x != nil {
+		return x.ToLocationCode
+	}
+	return ""
+}
+
+func (x *TransferStockRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *TransferStockRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *TransferStockRequest) GetInTransit() bool {
+	if x != nil {
+		return x.InTransit
+	}
+	return false
+}
+
+func (x *TransferStockRequest) GetExpectedFromVersion() int64 {
+	if x != nil {
+		return x.ExpectedFromVersion
+	}
+	return 0
+}
+
+type TransferStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferStockResponse) Reset() {
+	*x = TransferStockResponse{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferStockResponse) ProtoMessage() {}
+
+func (x *TransferStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferStockResponse.ProtoReflect.Descriptor instead.
+func (*TransferStockResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TransferStockResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *TransferStockResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type ReceiveTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveTransferRequest) Reset() {
+	*x = ReceiveTransferRequest{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveTransferRequest) ProtoMessage() {}
+
+func (x *ReceiveTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveTransferRequest.ProtoReflect.Descriptor instead.
+func (*ReceiveTransferRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReceiveTransferRequest) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+type ReceiveTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveTransferResponse) Reset() {
+	*x = ReceiveTransferResponse{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveTransferResponse) ProtoMessage() {}
+
+func (x *ReceiveTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveTransferResponse.ProtoReflect.Descriptor instead.
+func (*ReceiveTransferResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReceiveTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *ReceiveTransferResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type CancelTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTransferRequest) Reset() {
+	*x = CancelTransferRequest{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransferRequest) ProtoMessage() {}
+
+func (x *CancelTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransferRequest.ProtoReflect.Descriptor instead.
+func (*CancelTransferRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CancelTransferRequest) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+type CancelTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Stock         *Stock                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTransferResponse) Reset() {
+	*x = CancelTransferResponse{}
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransferResponse) ProtoMessage() {}
+
+func (x *CancelTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_v1_stock_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransferResponse.ProtoReflect.Descriptor instead.
+func (*CancelTransferResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_v1_stock_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelTransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *CancelTransferResponse) GetStock() *Stock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+var File_inventory_v1_stock_admin_proto protoreflect.FileDescriptor
+
+const file_inventory_v1_stock_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x1einventory/v1/stock_admin.proto\x12\finventory.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18inventory/v1/stock.proto\"\xd5\x02\n" +
+	"\x12AdjustStockRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x127\n" +
+	"\x06reason\x18\x04 \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
+	"\treference\x18\x05 \x01(\tR\treference\x12%\n" +
+	"\x0eallow_negative\x18\x06 \x01(\bR\rallowNegative\x12B\n" +
+	"\x0fprev_updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\x12)\n" +
+	"\x10expected_version\x18\b \x01(\x03R\x0fexpectedVersion\"@\n" +
+	"\x13AdjustStockResponse\x12)\n" +
+	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"\xba\x02\n" +
+	"\x0fSetStockRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12#\n" +
+	"\rnew_available\x18\x03 \x01(\x03R\fnewAvailable\x127\n" +
+	"\x06reason\x18\x04 \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
+	"\treference\x18\x05 \x01(\tR\treference\x12B\n" +
+	"\x0fprev_updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\x12)\n" +
+	"\x10expected_version\x18\a \x01(\x03R\x0fexpectedVersion\"=\n" +
+	"\x10SetStockResponse\x12)\n" +
+	"\x05stock\x18\x01 \x01(\v2\x13.inventory.v1.StockR\x05stock\"\xd2\x02\n" +
+	"\x0fBatchAdjustLine\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x127\n" +
 	"\x06reason\x18\x04 \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
 	"\treference\x18\x05 \x01(\tR\treference\x12%\n" +
 	"\x0eallow_negative\x18\x06 \x01(\bR\rallowNegative\x12B\n" +
-	"\x0fprev_updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\"N\n" +
+	"\x0fprev_updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rprevUpdatedAt\x12)\n" +
+	"\x10expected_version\x18\b \x01(\x03R\x0fexpectedVersion\"{\n" +
 	"\x17BatchAdjustStockRequest\x123\n" +
-	"\x05lines\x18\x01 \x03(\v2\x1d.inventory.v1.BatchAdjustLineR\x05lines\"G\n" +
+	"\x05lines\x18\x01 \x03(\v2\x1d.inventory.v1.BatchAdjustLineR\x05lines\x12+\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x17.inventory.v1.BatchModeR\x04mode\"\xbe\x01\n" +
+	"\tLineError\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12<\n" +
+	"\n" +
+	"violations\x18\x04 \x03(\v2\x1c.inventory.v1.FieldViolationR\n" +
+	"violations\x12-\n" +
+	"\acurrent\x18\x05 \x01(\v2\x13.inventory.v1.StockR\acurrent\"\xd1\x01\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12@\n" +
+	"\x06params\x18\x04 \x03(\v2(.inventory.v1.FieldViolation.ParamsEntryR\x06params\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x87\x01\n" +
+	"\x15BatchAdjustLineResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\x12-\n" +
+	"\x05error\x18\x03 \x01(\v2\x17.inventory.v1.LineErrorR\x05error\"\xb8\x01\n" +
 	"\x18BatchAdjustStockResponse\x12+\n" +
-	"\x06stocks\x18\x01 \x03(\v2\x13.inventory.v1.StockR\x06stocks*\xa1\x01\n" +
+	"\x06stocks\x18\x01 \x03(\v2\x13.inventory.v1.StockR\x06stocks\x12=\n" +
+	"\aresults\x18\x02 \x03(\v2#.inventory.v1.BatchAdjustLineResultR\aresults\x12\x18\n" +
+	"\aapplied\x18\x03 \x01(\x05R\aapplied\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\"\xc9\x02\n" +
+	"\rStockMovement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x03 \x01(\tR\flocationCode\x12\x14\n" +
+	"\x05delta\x18\x04 \x01(\x03R\x05delta\x12\x16\n" +
+	"\x06before\x18\x05 \x01(\x03R\x06before\x12\x14\n" +
+	"\x05after\x18\x06 \x01(\x03R\x05after\x127\n" +
+	"\x06reason\x18\a \x01(\x0e2\x1f.inventory.v1.StockChangeReasonR\x06reason\x12\x1c\n" +
+	"\treference\x18\b \x01(\tR\treference\x12\x14\n" +
+	"\x05actor\x18\t \x01(\tR\x05actor\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd2\x02\n" +
+	"\x19ListStockMovementsRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12#\n" +
+	"\rlocation_code\x18\x02 \x01(\tR\flocationCode\x129\n" +
+	"\areasons\x18\x03 \x03(\x0e2\x1f.inventory.v1.StockChangeReasonR\areasons\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x120\n" +
+	"\x05since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"\x7f\n" +
+	"\x1aListStockMovementsResponse\x129\n" +
+	"\tmovements\x18\x01 \x03(\v2\x1b.inventory.v1.StockMovementR\tmovements\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xff\x02\n" +
+	"\bTransfer\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\x12\x17\n" +
+	"\aitem_id\x18\x02 \x01(\x03R\x06itemId\x12,\n" +
+	"\x12from_location_code\x18\x03 \x01(\tR\x10fromLocationCode\x12(\n" +
+	"\x10to_location_code\x18\x04 \x01(\tR\x0etoLocationCode\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x121\n" +
+	"\x05state\x18\x06 \x01(\x0e2\x1b.inventory.v1.TransferStateR\x05state\x12\x1c\n" +
+	"\treference\x18\a \x01(\tR\treference\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x94\x02\n" +
+	"\x14TransferStockRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\x03R\x06itemId\x12,\n" +
+	"\x12from_location_code\x18\x02 \x01(\tR\x10fromLocationCode\x12(\n" +
+	"\x10to_location_code\x18\x03 \x01(\tR\x0etoLocationCode\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x03R\bquantity\x12\x1c\n" +
+	"\treference\x18\x05 \x01(\tR\treference\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\x06 \x01(\bR\tinTransit\x122\n" +
+	"\x15expected_from_version\x18\a \x01(\x03R\x13expectedFromVersion\"v\n" +
+	"\x15TransferStockResponse\x122\n" +
+	"\btransfer\x18\x01 \x01(\v2\x16.inventory.v1.TransferR\btransfer\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\"9\n" +
+	"\x16ReceiveTransferRequest\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\"x\n" +
+	"\x17ReceiveTransferResponse\x122\n" +
+	"\btransfer\x18\x01 \x01(\v2\x16.inventory.v1.TransferR\btransfer\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock\"8\n" +
+	"\x15CancelTransferRequest\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\"w\n" +
+	"\x16CancelTransferResponse\x122\n" +
+	"\btransfer\x18\x01 \x01(\v2\x16.inventory.v1.TransferR\btransfer\x12)\n" +
+	"\x05stock\x18\x02 \x01(\v2\x13.inventory.v1.StockR\x05stock*\xd3\x01\n" +
 	"\x11StockChangeReason\x12#\n" +
 	"\x1fSTOCK_CHANGE_REASON_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14STOCK_CHANGE_RECEIPT\x10\x01\x12\x1b\n" +
 	"\x17STOCK_CHANGE_CORRECTION\x10\x02\x12\x17\n" +
 	"\x13STOCK_CHANGE_RETURN\x10\x03\x12\x17\n" +
-	"\x13STOCK_CHANGE_MANUAL\x10\x042\x95\x02\n" +
+	"\x13STOCK_CHANGE_MANUAL\x10\x04\x12\x15\n" +
+	"\x11STOCK_CHANGE_SALE\x10\x05\x12\x19\n" +
+	"\x15STOCK_CHANGE_TRANSFER\x10\x06*b\n" +
+	"\tBatchMode\x12\x1a\n" +
+	"\x16BATCH_MODE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19BATCH_MODE_ALL_OR_NOTHING\x10\x01\x12\x1a\n" +
+	"\x16BATCH_MODE_BEST_EFFORT\x10\x02*\x89\x01\n" +
+	"\rTransferState\x12\x1e\n" +
+	"\x1aTRANSFER_STATE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19TRANSFER_STATE_IN_TRANSIT\x10\x01\x12\x1b\n" +
+	"\x17TRANSFER_STATE_RECEIVED\x10\x02\x12\x1c\n" +
+	"\x18TRANSFER_STATE_CANCELLED\x10\x032\x95\x05\n" +
 	"\x11StockAdminService\x12R\n" +
 	"\vAdjustStock\x12 .inventory.v1.AdjustStockRequest\x1a!.inventory.v1.AdjustStockResponse\x12I\n" +
 	"\bSetStock\x12\x1d.inventory.v1.SetStockRequest\x1a\x1e.inventory.v1.SetStockResponse\x12a\n" +
-	"\x10BatchAdjustStock\x12%.inventory.v1.BatchAdjustStockRequest\x1a&.inventory.v1.BatchAdjustStockResponseB%Z#ecommerce/v2/gen/inventory/v1;invpbb\x06proto3"
+	"\x10BatchAdjustStock\x12%.inventory.v1.BatchAdjustStockRequest\x1a&.inventory.v1.BatchAdjustStockResponse\x12g\n" +
+	"\x12ListStockMovements\x12'.inventory.v1.ListStockMovementsRequest\x1a(.inventory.v1.ListStockMovementsResponse\x12X\n" +
+	"\rTransferStock\x12\".inventory.v1.TransferStockRequest\x1a#.inventory.v1.TransferStockResponse\x12^\n" +
+	"\x0fReceiveTransfer\x12$.inventory.v1.ReceiveTransferRequest\x1a%.inventory.v1.ReceiveTransferResponse\x12[\n" +
+	"\x0eCancelTransfer\x12#.inventory.v1.CancelTransferRequest\x1a$.inventory.v1.CancelTransferResponseB7Z5github.com/YanMak/ecommerce/v2/gen/inventory/v1;invpbb\x06proto3"
 
 var (
 	file_inventory_v1_stock_admin_proto_rawDescOnce sync.Once
@@ -584,42 +1782,88 @@ func file_inventory_v1_stock_admin_proto_rawDescGZIP() []byte {
 	return file_inventory_v1_stock_admin_proto_rawDescData
 }
 
-var file_inventory_v1_stock_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_inventory_v1_stock_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_inventory_v1_stock_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_inventory_v1_stock_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_inventory_v1_stock_admin_proto_goTypes = []any{
-	(StockChangeReason)(0),           // 0: inventory.v1.StockChangeReason
-	(*AdjustStockRequest)(nil),       // 1: inventory.v1.AdjustStockRequest
-	(*AdjustStockResponse)(nil),      // 2: inventory.v1.AdjustStockResponse
-	(*SetStockRequest)(nil),          // 3: inventory.v1.SetStockRequest
-	(*SetStockResponse)(nil),         // 4: inventory.v1.SetStockResponse
-	(*BatchAdjustLine)(nil),          // 5: inventory.v1.BatchAdjustLine
-	(*BatchAdjustStockRequest)(nil),  // 6: inventory.v1.BatchAdjustStockRequest
-	(*BatchAdjustStockResponse)(nil), // 7: inventory.v1.BatchAdjustStockResponse
-	(*timestamppb.Timestamp)(nil),    // 8: google.protobuf.Timestamp
-	(*Stock)(nil),                    // 9: inventory.v1.Stock
+	(StockChangeReason)(0),             // 0: inventory.v1.StockChangeReason
+	(BatchMode)(0),                     // 1: inventory.v1.BatchMode
+	(TransferState)(0),                 // 2: inventory.v1.TransferState
+	(*AdjustStockRequest)(nil),         // 3: inventory.v1.AdjustStockRequest
+	(*AdjustStockResponse)(nil),        // 4: inventory.v1.AdjustStockResponse
+	(*SetStockRequest)(nil),            // 5: inventory.v1.SetStockRequest
+	(*SetStockResponse)(nil),           // 6: inventory.v1.SetStockResponse
+	(*BatchAdjustLine)(nil),            // 7: inventory.v1.BatchAdjustLine
+	(*BatchAdjustStockRequest)(nil),    // 8: inventory.v1.BatchAdjustStockRequest
+	(*LineError)(nil),                  // 9: inventory.v1.LineError
+	(*FieldViolation)(nil),             // 10: inventory.v1.FieldViolation
+	(*BatchAdjustLineResult)(nil),      // 11: inventory.v1.BatchAdjustLineResult
+	(*BatchAdjustStockResponse)(nil),   // 12: inventory.v1.BatchAdjustStockResponse
+	(*StockMovement)(nil),              // 13: inventory.v1.StockMovement
+	(*ListStockMovementsRequest)(nil),  // 14: inventory.v1.ListStockMovementsRequest
+	(*ListStockMovementsResponse)(nil), // 15: inventory.v1.ListStockMovementsResponse
+	(*Transfer)(nil),                   // 16: inventory.v1.Transfer
+	(*TransferStockRequest)(nil),       // 17: inventory.v1.TransferStockRequest
+	(*TransferStockResponse)(nil),      // 18: inventory.v1.TransferStockResponse
+	(*ReceiveTransferRequest)(nil),     // 19: inventory.v1.ReceiveTransferRequest
+	(*ReceiveTransferResponse)(nil),    // 20: inventory.v1.ReceiveTransferResponse
+	(*CancelTransferRequest)(nil),      // 21: inventory.v1.CancelTransferRequest
+	(*CancelTransferResponse)(nil),     // 22: inventory.v1.CancelTransferResponse
+	nil,                                // 23: inventory.v1.FieldViolation.ParamsEntry
+	(*timestamppb.Timestamp)(nil),      // 24: google.protobuf.Timestamp
+	(*Stock)(nil),                      // 25: inventory.v1.Stock
 }
 var file_inventory_v1_stock_admin_proto_depIdxs = []int32{
 	0,  // 0: inventory.v1.AdjustStockRequest.reason:type_name -> inventory.v1.StockChangeReason
-	8,  // 1: inventory.v1.AdjustStockRequest.prev_updated_at:type_name -> google.protobuf.Timestamp
-	9,  // 2: inventory.v1.AdjustStockResponse.stock:type_name -> inventory.v1.Stock
+	24, // 1: inventory.v1.AdjustStockRequest.prev_updated_at:type_name -> google.protobuf.Timestamp
+	25, // 2: inventory.v1.AdjustStockResponse.stock:type_name -> inventory.v1.Stock
 	0,  // 3: inventory.v1.SetStockRequest.reason:type_name -> inventory.v1.StockChangeReason
-	8,  // 4: inventory.v1.SetStockRequest.prev_updated_at:type_name -> google.protobuf.Timestamp
-	9,  // 5: inventory.v1.SetStockResponse.stock:type_name -> inventory.v1.Stock
+	24, // 4: inventory.v1.SetStockRequest.prev_updated_at:type_name -> google.protobuf.Timestamp
+	25, // 5: inventory.v1.SetStockResponse.stock:type_name -> inventory.v1.Stock
 	0,  // 6: inventory.v1.BatchAdjustLine.reason:type_name -> inventory.v1.StockChangeReason
-	8,  // 7: inventory.v1.BatchAdjustLine.prev_updated_at:type_name -> google.protobuf.Timestamp
-	5,  // 8: inventory.v1.BatchAdjustStockRequest.lines:type_name -> inventory.v1.BatchAdjustLine
-	9,  // 9: inventory.v1.BatchAdjustStockResponse.stocks:type_name -> inventory.v1.Stock
-	1,  // 10: inventory.v1.StockAdminService.AdjustStock:input_type -> inventory.v1.AdjustStockRequest
-	3,  // 11: inventory.v1.StockAdminService.SetStock:input_type -> inventory.v1.SetStockRequest
-	6,  // 12: inventory.v1.StockAdminService.BatchAdjustStock:input_type -> inventory.v1.BatchAdjustStockRequest
-	2,  // 13: inventory.v1.StockAdminService.AdjustStock:output_type -> inventory.v1.AdjustStockResponse
-	4,  // 14: inventory.v1.StockAdminService.SetStock:output_type -> inventory.v1.SetStockResponse
-	7,  // 15: inventory.v1.StockAdminService.BatchAdjustStock:output_type -> inventory.v1.BatchAdjustStockResponse
-	13, // [13:16] is the sub-list for method output_type
-	10, // [10:13] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	24, // 7: inventory.v1.BatchAdjustLine.prev_updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 8: inventory.v1.BatchAdjustStockRequest.lines:type_name -> inventory.v1.BatchAdjustLine
+	1,  // 9: inventory.v1.BatchAdjustStockRequest.mode:type_name -> inventory.v1.BatchMode
+	10, // 10: inventory.v1.LineError.violations:type_name -> inventory.v1.FieldViolation
+	25, // 11: inventory.v1.LineError.current:type_name -> inventory.v1.Stock
+	23, // 12: inventory.v1.FieldViolation.params:type_name -> inventory.v1.FieldViolation.ParamsEntry
+	25, // 13: inventory.v1.BatchAdjustLineResult.stock:type_name -> inventory.v1.Stock
+	9,  // 14: inventory.v1.BatchAdjustLineResult.error:type_name -> inventory.v1.LineError
+	25, // 15: inventory.v1.BatchAdjustStockResponse.stocks:type_name -> inventory.v1.Stock
+	11, // 16: inventory.v1.BatchAdjustStockResponse.results:type_name -> inventory.v1.BatchAdjustLineResult
+	0,  // 17: inventory.v1.StockMovement.reason:type_name -> inventory.v1.StockChangeReason
+	24, // 18: inventory.v1.StockMovement.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 19: inventory.v1.ListStockMovementsRequest.reasons:type_name -> inventory.v1.StockChangeReason
+	24, // 20: inventory.v1.ListStockMovementsRequest.since:type_name -> google.protobuf.Timestamp
+	24, // 21: inventory.v1.ListStockMovementsRequest.until:type_name -> google.protobuf.Timestamp
+	13, // 22: inventory.v1.ListStockMovementsResponse.movements:type_name -> inventory.v1.StockMovement
+	2,  // 23: inventory.v1.Transfer.state:type_name -> inventory.v1.TransferState
+	24, // 24: inventory.v1.Transfer.created_at:type_name -> google.protobuf.Timestamp
+	24, // 25: inventory.v1.Transfer.updated_at:type_name -> google.protobuf.Timestamp
+	16, // 26: inventory.v1.TransferStockResponse.transfer:type_name -> inventory.v1.Transfer
+	25, // 27: inventory.v1.TransferStockResponse.stock:type_name -> inventory.v1.Stock
+	16, // 28: inventory.v1.ReceiveTransferResponse.transfer:type_name -> inventory.v1.Transfer
+	25, // 29: inventory.v1.ReceiveTransferResponse.stock:type_name -> inventory.v1.Stock
+	16, // 30: inventory.v1.CancelTransferResponse.transfer:type_name -> inventory.v1.Transfer
+	25, // 31: inventory.v1.CancelTransferResponse.stock:type_name -> inventory.v1.Stock
+	3,  // 32: inventory.v1.StockAdminService.AdjustStock:input_type -> inventory.v1.AdjustStockRequest
+	5,  // 33: inventory.v1.StockAdminService.SetStock:input_type -> inventory.v1.SetStockRequest
+	8,  // 34: inventory.v1.StockAdminService.BatchAdjustStock:input_type -> inventory.v1.BatchAdjustStockRequest
+	14, // 35: inventory.v1.StockAdminService.ListStockMovements:input_type -> inventory.v1.ListStockMovementsRequest
+	17, // 36: inventory.v1.StockAdminService.TransferStock:input_type -> inventory.v1.TransferStockRequest
+	19, // 37: inventory.v1.StockAdminService.ReceiveTransfer:input_type -> inventory.v1.ReceiveTransferRequest
+	21, // 38: inventory.v1.StockAdminService.CancelTransfer:input_type -> inventory.v1.CancelTransferRequest
+	4,  // 39: inventory.v1.StockAdminService.AdjustStock:output_type -> inventory.v1.AdjustStockResponse
+	6,  // 40: inventory.v1.StockAdminService.SetStock:output_type -> inventory.v1.SetStockResponse
+	12, // 41: inventory.v1.StockAdminService.BatchAdjustStock:output_type -> inventory.v1.BatchAdjustStockResponse
+	15, // 42: inventory.v1.StockAdminService.ListStockMovements:output_type -> inventory.v1.ListStockMovementsResponse
+	18, // 43: inventory.v1.StockAdminService.TransferStock:output_type -> inventory.v1.TransferStockResponse
+	20, // 44: inventory.v1.StockAdminService.ReceiveTransfer:output_type -> inventory.v1.ReceiveTransferResponse
+	22, // 45: inventory.v1.StockAdminService.CancelTransfer:output_type -> inventory.v1.CancelTransferResponse
+	39, // [39:46] is the sub-list for method output_type
+	32, // [32:39] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_inventory_v1_stock_admin_proto_init() }
@@ -633,8 +1877,8 @@ func file_inventory_v1_stock_admin_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_v1_stock_admin_proto_rawDesc), len(file_inventory_v1_stock_admin_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      3,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
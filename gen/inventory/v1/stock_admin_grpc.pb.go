@@ -19,9 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StockAdminService_AdjustStock_FullMethodName      = "/inventory.v1.StockAdminService/AdjustStock"
-	StockAdminService_SetStock_FullMethodName         = "/inventory.v1.StockAdminService/SetStock"
-	StockAdminService_BatchAdjustStock_FullMethodName = "/inventory.v1.StockAdminService/BatchAdjustStock"
+	StockAdminService_AdjustStock_FullMethodName        = "/inventory.v1.StockAdminService/AdjustStock"
+	StockAdminService_SetStock_FullMethodName           = "/inventory.v1.StockAdminService/SetStock"
+	StockAdminService_BatchAdjustStock_FullMethodName   = "/inventory.v1.StockAdminService/BatchAdjustStock"
+	StockAdminService_ListStockMovements_FullMethodName = "/inventory.v1.StockAdminService/ListStockMovements"
+	StockAdminService_TransferStock_FullMethodName      = "/inventory.v1.StockAdminService/TransferStock"
+	StockAdminService_ReceiveTransfer_FullMethodName    = "/inventory.v1.StockAdminService/ReceiveTransfer"
+	StockAdminService_CancelTransfer_FullMethodName     = "/inventory.v1.StockAdminService/CancelTransfer"
 )
 
 // StockAdminServiceClient is the client API for StockAdminService service.
@@ -30,10 +34,17 @@ const (
 //
 // Ошибки (конвенция):
 // INVALID_ARGUMENT, NOT_FOUND, ABORTED, FAILED_PRECONDITION, RESOURCE_EXHAUSTED, INTERNAL
+// ABORTED (prev_updated_at/expected_version не совпали) несёт в details ErrorInfo reason "VERSION_CONFLICT"
+// и актуальный Stock товара. Повтор с idempotency-key, пока первый запрос ещё выполняется, —
+// UNAVAILABLE с reason "IDEMPOTENCY_IN_PROGRESS" и RetryInfo: повторить тем же ключом.
 type StockAdminServiceClient interface {
 	AdjustStock(ctx context.Context, in *AdjustStockRequest, opts ...grpc.CallOption) (*AdjustStockResponse, error)
 	SetStock(ctx context.Context, in *SetStockRequest, opts ...grpc.CallOption) (*SetStockResponse, error)
 	BatchAdjustStock(ctx context.Context, in *BatchAdjustStockRequest, opts ...grpc.CallOption) (*BatchAdjustStockResponse, error)
+	ListStockMovements(ctx context.Context, in *ListStockMovementsRequest, opts ...grpc.CallOption) (*ListStockMovementsResponse, error)
+	TransferStock(ctx context.Context, in *TransferStockRequest, opts ...grpc.CallOption) (*TransferStockResponse, error)
+	ReceiveTransfer(ctx context.Context, in *ReceiveTransferRequest, opts ...grpc.CallOption) (*ReceiveTransferResponse, error)
+	CancelTransfer(ctx context.Context, in *CancelTransferRequest, opts ...grpc.CallOption) (*CancelTransferResponse, error)
 }
 
 type stockAdminServiceClient struct {
@@ -74,16 +85,63 @@ func (c *stockAdminServiceClient) BatchAdjustStock(ctx context.Context, in *Batc
 	return out, nil
 }
 
+func (c *stockAdminServiceClient) ListStockMovements(ctx context.Context, in *ListStockMovementsRequest, opts ...grpc.CallOption) (*ListStockMovementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStockMovementsResponse)
+	err := c.cc.Invoke(ctx, StockAdminService_ListStockMovements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockAdminServiceClient) TransferStock(ctx context.Context, in *TransferStockRequest, opts ...grpc.CallOption) (*TransferStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferStockResponse)
+	err := c.cc.Invoke(ctx, StockAdminService_TransferStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockAdminServiceClient) ReceiveTransfer(ctx context.Context, in *ReceiveTransferRequest, opts ...grpc.CallOption) (*ReceiveTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReceiveTransferResponse)
+	err := c.cc.Invoke(ctx, StockAdminService_ReceiveTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockAdminServiceClient) CancelTransfer(ctx context.Context, in *CancelTransferRequest, opts ...grpc.CallOption) (*CancelTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelTransferResponse)
+	err := c.cc.Invoke(ctx, StockAdminService_CancelTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StockAdminServiceServer is the server API for StockAdminService service.
 // All implementations must embed UnimplementedStockAdminServiceServer
 // for forward compatibility.
 //
 // Ошибки (конвенция):
 // INVALID_ARGUMENT, NOT_FOUND, ABORTED, FAILED_PRECONDITION, RESOURCE_EXHAUSTED, INTERNAL
+// ABORTED (prev_updated_at/expected_version не совпали) несёт в details ErrorInfo reason "VERSION_CONFLICT"
+// и актуальный Stock товара. Повтор с idempotency-key, пока первый запрос ещё выполняется, —
+// UNAVAILABLE с reason "IDEMPOTENCY_IN_PROGRESS" и RetryInfo: повторить тем же ключом.
 type StockAdminServiceServer interface {
 	AdjustStock(context.Context, *AdjustStockRequest) (*AdjustStockResponse, error)
 	SetStock(context.Context, *SetStockRequest) (*SetStockResponse, error)
 	BatchAdjustStock(context.Context, *BatchAdjustStockRequest) (*BatchAdjustStockResponse, error)
+	ListStockMovements(context.Context, *ListStockMovementsRequest) (*ListStockMovementsResponse, error)
+	TransferStock(context.Context, *TransferStockRequest) (*TransferStockResponse, error)
+	ReceiveTransfer(context.Context, *ReceiveTransferRequest) (*ReceiveTransferResponse, error)
+	CancelTransfer(context.Context, *CancelTransferRequest) (*CancelTransferResponse, error)
 	mustEmbedUnimplementedStockAdminServiceServer()
 }
 
@@ -103,6 +161,18 @@ func (UnimplementedStockAdminServiceServer) SetStock(context.Context, *SetStockR
 func (UnimplementedStockAdminServiceServer) BatchAdjustStock(context.Context, *BatchAdjustStockRequest) (*BatchAdjustStockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchAdjustStock not implemented")
 }
+func (UnimplementedStockAdminServiceServer) ListStockMovements(context.Context, *ListStockMovementsRequest) (*ListStockMovementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStockMovements not implemented")
+}
+func (UnimplementedStockAdminServiceServer) TransferStock(context.Context, *TransferStockRequest) (*TransferStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferStock not implemented")
+}
+func (UnimplementedStockAdminServiceServer) ReceiveTransfer(context.Context, *ReceiveTransferRequest) (*ReceiveTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveTransfer not implemented")
+}
+func (UnimplementedStockAdminServiceServer) CancelTransfer(context.Context, *CancelTransferRequest) (*CancelTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTransfer not implemented")
+}
 func (UnimplementedStockAdminServiceServer) mustEmbedUnimplementedStockAdminServiceServer() {}
 func (UnimplementedStockAdminServiceServer) testEmbeddedByValue()                           {}
 
@@ -178,6 +248,78 @@ func _StockAdminService_BatchAdjustStock_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StockAdminService_ListStockMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStockMovementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockAdminServiceServer).ListStockMovements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockAdminService_ListStockMovements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockAdminServiceServer).ListStockMovements(ctx, req.(*ListStockMovementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockAdminService_TransferStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockAdminServiceServer).TransferStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockAdminService_TransferStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockAdminServiceServer).TransferStock(ctx, req.(*TransferStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockAdminService_ReceiveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockAdminServiceServer).ReceiveTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockAdminService_ReceiveTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockAdminServiceServer).ReceiveTransfer(ctx, req.(*ReceiveTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockAdminService_CancelTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockAdminServiceServer).CancelTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockAdminService_CancelTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockAdminServiceServer).CancelTransfer(ctx, req.(*CancelTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StockAdminService_ServiceDesc is the grpc.ServiceDesc for StockAdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -197,6 +339,22 @@ var StockAdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchAdjustStock",
 			Handler:    _StockAdminService_BatchAdjustStock_Handler,
 		},
+		{
+			MethodName: "ListStockMovements",
+			Handler:    _StockAdminService_ListStockMovements_Handler,
+		},
+		{
+			MethodName: "TransferStock",
+			Handler:    _StockAdminService_TransferStock_Handler,
+		},
+		{
+			MethodName: "ReceiveTransfer",
+			Handler:    _StockAdminService_ReceiveTransfer_Handler,
+		},
+		{
+			MethodName: "CancelTransfer",
+			Handler:    _StockAdminService_CancelTransfer_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "inventory/v1/stock_admin.proto",
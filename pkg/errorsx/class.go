@@ -48,43 +48,48 @@ func (e *E) Error() string {
 func (e *E) Unwrap() error { return e.Err }
 
 // ---------- Публичные конструкторы (удобные шорткаты) ----------
+// Имена с суффиксом Code — чтобы не пересекаться с обёртками из errors.go (там аргумент — сообщение,
+// здесь — машинный код): errorsx.NotFoundCode("ITEM_NOT_FOUND"). Обе формы дают одинаковый
+// KindOf и errors.Is(err, ErrNotFound).
 
 func Invalid(code string, v []Violation) error { return newE(KindInvalid, code, nil, v, nil) }
 func InvalidWithCause(code string, v []Violation, cause error) error {
 	return newE(KindInvalid, code, nil, v, cause)
 }
 
-func NotFound(code string) error { return newE(KindNotFound, code, nil, nil, nil) }
+func NotFoundCode(code string) error { return newE(KindNotFound, code, nil, nil, nil) }
 func NotFoundWithCause(code string, cause error) error {
 	return newE(KindNotFound, code, nil, nil, cause)
 }
 
-func AlreadyExists(code string) error { return newE(KindAlreadyExists, code, nil, nil, nil) }
+func AlreadyExistsCode(code string) error { return newE(KindAlreadyExists, code, nil, nil, nil) }
 func AlreadyExistsWithCause(code string, cause error) error {
 	return newE(KindAlreadyExists, code, nil, nil, cause)
 }
 
-func Conflict(code string) error { return newE(KindConflict, code, nil, nil, nil) }
+func ConflictCode(code string) error { return newE(KindConflict, code, nil, nil, nil) }
 func ConflictWithCause(code string, cause error) error {
 	return newE(KindConflict, code, nil, nil, cause)
 }
 
-func Aborted(code string) error { return newE(KindAborted, code, nil, nil, nil) }
+func AbortedCode(code string) error { return newE(KindAborted, code, nil, nil, nil) }
 func AbortedWithCause(code string, cause error) error {
 	return newE(KindAborted, code, nil, nil, cause)
 }
 
-func FailedPrecondition(code string) error { return newE(KindFailedPrecondition, code, nil, nil, nil) }
+func FailedPreconditionCode(code string) error {
+	return newE(KindFailedPrecondition, code, nil, nil, nil)
+}
 func FailedPreconditionWithCause(code string, cause error) error {
 	return newE(KindFailedPrecondition, code, nil, nil, cause)
 }
 
-func Unauthenticated(code string) error { return newE(KindUnauthenticated, code, nil, nil, nil) }
+func UnauthenticatedCode(code string) error { return newE(KindUnauthenticated, code, nil, nil, nil) }
 func UnauthenticatedWithCause(code string, cause error) error {
 	return newE(KindUnauthenticated, code, nil, nil, cause)
 }
 
-func PermissionDenied(code string) error { return newE(KindPermission, code, nil, nil, nil) }
+func PermissionDeniedCode(code string) error { return newE(KindPermission, code, nil, nil, nil) }
 func PermissionDeniedWithCause(code string, cause error) error {
 	return newE(KindPermission, code, nil, nil, cause)
 }
@@ -94,12 +99,12 @@ func RateLimitedWithCause(code string, cause error) error {
 	return newE(KindRateLimited, code, nil, nil, cause)
 }
 
-func Unavailable(code string) error { return newE(KindUnavailable, code, nil, nil, nil) }
+func UnavailableCode(code string) error { return newE(KindUnavailable, code, nil, nil, nil) }
 func UnavailableWithCause(code string, cause error) error {
 	return newE(KindUnavailable, code, nil, nil, cause)
 }
 
-func Internal(code string) error { return newE(KindInternal, code, nil, nil, nil) }
+func InternalCode(code string) error { return newE(KindInternal, code, nil, nil, nil) }
 func InternalWithCause(code string, cause error) error {
 	return newE(KindInternal, code, nil, nil, cause)
 }
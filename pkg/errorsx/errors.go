@@ -96,23 +96,23 @@ func Internalf(format string, a ...any) error {
 
 // ---------- Примечания по использованию ----------
 //
-// 1) В use case возвращай «класс» через Wrap*:
+// В пакете два уровня API, совместимых через errors.Is:
+//
+// 1) Обёртки сентинелов (этот файл) — класс + человекочитаемое сообщение:
 //    - отсутствие записи:        return errorsx.NotFoundf("item %d", id)
 //    - уникальный конфликт:      return errorsx.AlreadyExists("slug taken")
 //    - оптимистическая блок.:    return errorsx.Aborted("version mismatch")
 //    - инвариант/бизнес-правило: return errorsx.FailedPrecondition("would become negative")
-//    - плохой ввод (одно поле):
-//         return errorsx.InvalidArgument("price_cents must be >= 0")
-//       (для множественной валидации используем ValidationError — добавим в отдельном файле validation.go)
+//    - плохой ввод (одно поле):  return errorsx.InvalidArgument("price_cents must be >= 0")
+//
+// 2) Классифицированные ошибки *E (class.go) — класс + машинный Code + Retryable (+ Violations):
+//    - return errorsx.NotFoundCode("ITEM_NOT_FOUND")
+//    - return errorsx.NotFoundWithCause("ITEM_NOT_FOUND", err)
+//    - return errorsx.Wrap(errorsx.KindInternal, "DB_DOWN", true, nil, err)
+//    Имена с суффиксом Code — чтобы не пересекаться с обёртками из п.1.
 //
-// 2) В gRPC-сервере маппим коды:
-//    if errorsx.IsInvalidArgument(err)   -> codes.InvalidArgument
-//    if errorsx.IsNotFound(err)          -> codes.NotFound
-//    if errorsx.IsAlreadyExists(err)     -> codes.AlreadyExists
-//    if errorsx.IsAborted(err)           -> codes.Aborted
-//    if errorsx.IsFailedPrecondition(err)-> codes.FailedPrecondition
-//    ...
+// 3) Множественная валидация — ValidationError (validation.go), Unwrap() -> ErrInvalidArgument.
 //
-// 3) Для множественной валидации (несколько полей) — будет pkg/errsx/validation.go:
-//    ValidationError с Unwrap()->ErrInvalidArgument и списком Violations.
-//    В gRPC упакуем через pkg/grpcx.InvalidArgumentError(...).
+// 4) Транспорт — pkg/grpcx: grpcx.ToStatus / grpcx.FromStatus (или интерсепторы
+//    grpcx.Errors*Interceptor) переводят всё перечисленное в gRPC-статус и обратно без потерь;
+//    grpcx.InvalidArgumentError — INVALID_ARGUMENT с BadRequest из []Violation.
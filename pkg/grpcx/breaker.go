@@ -0,0 +1,307 @@
+package grpcx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// CodeCircuitOpen — errorsx.CodeOf ошибки отказа по открытому автомату.
+const CodeCircuitOpen = "CIRCUIT_OPEN"
+
+// BreakerState — состояние автомата одного ключа (target + метод).
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // вызовы идут, неудачи считаются в окне
+	BreakerOpen                         // вызовы сразу отклоняются до конца CoolDown
+	BreakerHalfOpen                     // пропускается HalfOpenProbes пробных вызовов
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerPolicy — когда размыкать и как восстанавливаться. Нулевые поля — дефолты
+// (см. DefaultBreakerPolicy).
+type BreakerPolicy struct {
+	Window         time.Duration // окно подсчёта неудач в closed
+	MinRequests    int           // меньше вызовов в окне — решение не принимается
+	FailureRatio   float64       // доля неудач в окне, при которой автомат размыкается
+	CoolDown       time.Duration // сколько держать open перед пробой
+	HalfOpenProbes int           // пробных вызовов в half-open; столько же успехов подряд замыкают
+
+	// IsFailure — считать ли ошибку вызова отказом бэкенда. nil — UNAVAILABLE,
+	// DEADLINE_EXCEEDED, INTERNAL, UNKNOWN; бизнес-ошибки (NOT_FOUND и т.п.) — успех.
+	IsFailure func(err error) bool
+	// OnStateChange — хук для логов/метрик; вызывается вне блокировок.
+	OnStateChange func(key string, from, to BreakerState)
+}
+
+// DefaultBreakerPolicy — размыкание при ≥50% неудач из ≥20 вызовов за 10s, пауза 5s, 1 проба.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		Window:         10 * time.Second,
+		MinRequests:    20,
+		FailureRatio:   0.5,
+		CoolDown:       5 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
+
+func (p BreakerPolicy) withDefaults() BreakerPolicy {
+	d := DefaultBreakerPolicy()
+	if p.Window <= 0 {
+		p.Window = d.Window
+	}
+	if p.MinRequests <= 0 {
+		p.MinRequests = d.MinRequests
+	}
+	if p.FailureRatio <= 0 || p.FailureRatio > 1 {
+		p.FailureRatio = d.FailureRatio
+	}
+	if p.CoolDown <= 0 {
+		p.CoolDown = d.CoolDown
+	}
+	if p.HalfOpenProbes <= 0 {
+		p.HalfOpenProbes = d.HalfOpenProbes
+	}
+	if p.IsFailure == nil {
+		p.IsFailure = defaultIsFailure
+	}
+	return p
+}
+
+func defaultIsFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ===== Ошибка =====
+
+// CircuitOpenError — вызов отклонён без обращения к серверу.
+// errorsx.KindOf == KindUnavailable, errorsx.CodeOf == CodeCircuitOpen, не retryable
+// (повтор сразу же упрётся в тот же автомат); для gRPC — UNAVAILABLE.
+type CircuitOpenError struct {
+	Key string
+	err error
+}
+
+func (e *CircuitOpenError) Error() string { return "circuit breaker open: " + e.Key }
+func (e *CircuitOpenError) Unwrap() error { return e.err }
+
+func (e *CircuitOpenError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.Error())
+}
+
+func circuitOpen(key string) error {
+	return &CircuitOpenError{Key: key, err: errorsx.Wrap(errorsx.KindUnavailable, CodeCircuitOpen, false, nil, nil)}
+}
+
+// ===== Автоматы =====
+
+// CircuitBreaker — набор автоматов по ключам (обычно target + полное имя метода).
+// Горутинобезопасен; один экземпляр на клиента/соединение.
+type CircuitBreaker struct {
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state BreakerState
+	gen   uint64 // меняется на каждом переходе: результаты «старых» вызовов не учитываются
+
+	windowStart     time.Time
+	total, failures int
+
+	openedAt         time.Time
+	probes, probesOK int // half-open: выдано проб / успешных
+}
+
+func NewCircuitBreaker(p BreakerPolicy) *CircuitBreaker {
+	return &CircuitBreaker{policy: p.withDefaults(), now: time.Now, circuits: make(map[string]*circuit)}
+}
+
+// State — текущее состояние ключа (неизвестный ключ — closed).
+func (b *CircuitBreaker) State(key string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		return BreakerClosed
+	}
+	if c.state == BreakerOpen && !b.now().Before(c.openedAt.Add(b.policy.CoolDown)) {
+		return BreakerHalfOpen
+	}
+	return c.state
+}
+
+// Do вызывает fn через автомат key. Открытый автомат — сразу *CircuitOpenError.
+// Вызовы, отменённые самим вызывающим (ctx), не считаются ни успехом, ни неудачей;
+// паника в fn считается неудачей (и идёт дальше) — иначе слот пробы half-open не освободился бы.
+func (b *CircuitBreaker) Do(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	gen, err := b.allow(key)
+	if err != nil {
+		return err
+	}
+	res := outcomeFailure
+	defer func() { b.report(key, gen, res) }()
+
+	err = fn(ctx)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		res = outcomeIgnored
+	case err != nil && b.policy.IsFailure(err):
+		res = outcomeFailure
+	default:
+		res = outcomeSuccess
+	}
+	return err
+}
+
+// UnaryClientInterceptor — автомат на каждую пару target + метод соединения.
+func (b *CircuitBreaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		key := method
+		if cc != nil {
+			key = cc.Target() + method
+		}
+		return b.Do(ctx, key, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// CircuitBreakerUnaryClientInterceptor — то же, что NewCircuitBreaker(p).UnaryClientInterceptor().
+func CircuitBreakerUnaryClientInterceptor(p BreakerPolicy) grpc.UnaryClientInterceptor {
+	return NewCircuitBreaker(p).UnaryClientInterceptor()
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+func (b *CircuitBreaker) allow(key string) (uint64, error) {
+	b.mu.Lock()
+	now := b.now()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{windowStart: now}
+		b.circuits[key] = c
+	}
+
+	var from BreakerState
+	changed := false
+	if c.state == BreakerOpen && !now.Before(c.openedAt.Add(b.policy.CoolDown)) {
+		from, changed = c.state, true
+		b.setStateLocked(c, BreakerHalfOpen, now)
+	}
+
+	var err error
+	switch c.state {
+	case BreakerOpen:
+		err = circuitOpen(key)
+	case BreakerHalfOpen:
+		if c.probes >= b.policy.HalfOpenProbes {
+			err = circuitOpen(key)
+		} else {
+			c.probes++
+		}
+	}
+	gen := c.gen
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(key, from, BreakerHalfOpen)
+	}
+	return gen, err
+}
+
+func (b *CircuitBreaker) report(key string, gen uint64, o outcome) {
+	b.mu.Lock()
+	now := b.now()
+	c := b.circuits[key]
+	if c == nil || c.gen != gen {
+		b.mu.Unlock()
+		return
+	}
+
+	from := c.state
+	switch c.state {
+	case BreakerClosed:
+		if now.Sub(c.windowStart) >= b.policy.Window {
+			c.windowStart, c.total, c.failures = now, 0, 0
+		}
+		if o == outcomeIgnored {
+			break
+		}
+		c.total++
+		if o == outcomeFailure {
+			c.failures++
+		}
+		if c.total >= b.policy.MinRequests && float64(c.failures) >= b.policy.FailureRatio*float64(c.total) {
+			b.setStateLocked(c, BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		switch o {
+		case outcomeFailure:
+			b.setStateLocked(c, BreakerOpen, now)
+		case outcomeSuccess:
+			c.probesOK++
+			if c.probesOK >= b.policy.HalfOpenProbes {
+				b.setStateLocked(c, BreakerClosed, now)
+			}
+		case outcomeIgnored:
+			c.probes-- // слот пробы освобождается для следующего вызова
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	if to != from {
+		b.notify(key, from, to)
+	}
+}
+
+func (b *CircuitBreaker) setStateLocked(c *circuit, s BreakerState, now time.Time) {
+	c.state = s
+	c.gen++
+	c.windowStart, c.total, c.failures = now, 0, 0
+	c.probes, c.probesOK = 0, 0
+	if s == BreakerOpen {
+		c.openedAt = now
+	}
+}
+
+func (b *CircuitBreaker) notify(key string, from, to BreakerState) {
+	if b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(key, from, to)
+	}
+}
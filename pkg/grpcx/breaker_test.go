@@ -0,0 +1,251 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+const breakerKey = "inventory/Get"
+
+var (
+	errDown     = status.Error(codes.Unavailable, "down")
+	errNotFound = status.Error(codes.NotFound, "nope")
+)
+
+// testBreaker — автомат с ручными часами и журналом переходов.
+type testBreaker struct {
+	*CircuitBreaker
+	now         time.Time
+	transitions []string
+}
+
+func newTestBreaker(p BreakerPolicy) *testBreaker {
+	tb := &testBreaker{now: time.Unix(0, 0)}
+	p.OnStateChange = func(key string, from, to BreakerState) {
+		tb.transitions = append(tb.transitions, from.String()+"->"+to.String())
+	}
+	tb.CircuitBreaker = NewCircuitBreaker(p)
+	tb.CircuitBreaker.now = func() time.Time { return tb.now }
+	return tb
+}
+
+func (tb *testBreaker) call(err error) error {
+	return tb.Do(context.Background(), breakerKey, func(context.Context) error { return err })
+}
+
+func (tb *testBreaker) wantState(t *testing.T, want BreakerState) {
+	t.Helper()
+	if got := tb.State(breakerKey); got != want {
+		t.Fatalf("state = %v, want %v", got, want)
+	}
+}
+
+func wantCircuitOpen(t *testing.T, err error) {
+	t.Helper()
+	var coe *CircuitOpenError
+	if !errors.As(err, &coe) || coe.Key != breakerKey {
+		t.Fatalf("err = %v, want *CircuitOpenError for %q", err, breakerKey)
+	}
+	if errorsx.CodeOf(err) != CodeCircuitOpen || errorsx.RetryableOf(err) || status.Code(err) != codes.Unavailable {
+		t.Fatalf("err = %v: code %q, retryable %v, grpc %v", err, errorsx.CodeOf(err), errorsx.RetryableOf(err), status.Code(err))
+	}
+}
+
+func TestBreakerOpensOnFailureRatio(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		outcomes []error
+		want     BreakerState
+	}{
+		{"too few requests", []error{errDown, errDown, errDown}, BreakerClosed},
+		{"ratio below threshold", []error{errDown, nil, nil, nil}, BreakerClosed},
+		{"ratio reached", []error{errDown, nil, errDown, nil}, BreakerOpen},
+		{"business errors are successes", []error{errNotFound, errNotFound, errNotFound, errNotFound}, BreakerClosed},
+		{"plain errors are UNKNOWN", []error{errors.New("x"), errors.New("x"), errors.New("x"), errors.New("x")}, BreakerOpen},
+		{"all failures", []error{errDown, status.Error(codes.DeadlineExceeded, ""), status.Error(codes.Internal, ""), status.Error(codes.Unknown, "")}, BreakerOpen},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tb := newTestBreaker(BreakerPolicy{MinRequests: 4, FailureRatio: 0.5})
+			for _, err := range tc.outcomes {
+				if got := tb.call(err); got != err {
+					t.Fatalf("Do = %v, want the call's own %v", got, err)
+				}
+			}
+			tb.wantState(t, tc.want)
+		})
+	}
+}
+
+func TestBreakerWindowResets(t *testing.T) {
+	tb := newTestBreaker(BreakerPolicy{Window: time.Second, MinRequests: 4, FailureRatio: 0.5})
+	tb.call(errDown)
+	tb.call(errDown)
+	tb.call(errDown)
+	tb.now = tb.now.Add(time.Second)
+	tb.call(errDown) // новое окно: 1 из 1, меньше MinRequests
+	tb.wantState(t, BreakerClosed)
+}
+
+func TestBreakerRecovery(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		probes []error
+		want   BreakerState
+		trans  []string
+	}{
+		{"probe succeeds", []error{nil}, BreakerClosed, []string{"closed->open", "open->half-open", "half-open->closed"}},
+		{"probe fails", []error{errDown}, BreakerOpen, []string{"closed->open", "open->half-open", "half-open->open"}},
+		{"business error closes", []error{errNotFound}, BreakerClosed, []string{"closed->open", "open->half-open", "half-open->closed"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tb := newTestBreaker(BreakerPolicy{MinRequests: 2, FailureRatio: 1, CoolDown: 5 * time.Second})
+			tb.call(errDown)
+			tb.call(errDown)
+			tb.wantState(t, BreakerOpen)
+
+			called := false
+			err := tb.Do(context.Background(), breakerKey, func(context.Context) error { called = true; return nil })
+			if called {
+				t.Fatal("open breaker let a call through")
+			}
+			wantCircuitOpen(t, err)
+
+			tb.now = tb.now.Add(5*time.Second - time.Nanosecond)
+			tb.wantState(t, BreakerOpen)
+			tb.now = tb.now.Add(time.Nanosecond)
+			tb.wantState(t, BreakerHalfOpen)
+
+			for _, p := range tc.probes {
+				tb.call(p)
+			}
+			tb.wantState(t, tc.want)
+			if !slices.Equal(tb.transitions, tc.trans) {
+				t.Fatalf("transitions = %v, want %v", tb.transitions, tc.trans)
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpenLimitsProbes(t *testing.T) {
+	tb := newTestBreaker(BreakerPolicy{MinRequests: 1, FailureRatio: 1, CoolDown: time.Second, HalfOpenProbes: 2})
+	tb.call(errDown)
+	tb.now = tb.now.Add(time.Second)
+
+	// Две пробы висят — третий вызов отклоняется.
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	done := make(chan error, 2)
+	for range 2 {
+		go func() {
+			done <- tb.Do(context.Background(), breakerKey, func(context.Context) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+	wantCircuitOpen(t, tb.call(nil))
+	close(release)
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Fatalf("probe: %v", err)
+		}
+	}
+	tb.wantState(t, BreakerClosed)
+}
+
+func TestBreakerHalfOpenReleasesSlot(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// probe — пробный вызов, который не должен оставить слот занятым.
+		probe func(tb *testBreaker)
+		want  BreakerState // состояние сразу после пробы
+	}{
+		{
+			name: "caller cancelled",
+			probe: func(tb *testBreaker) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				tb.Do(ctx, breakerKey, func(ctx context.Context) error { return status.FromContextError(ctx.Err()).Err() })
+			},
+			want: BreakerHalfOpen,
+		},
+		{
+			name: "panic",
+			probe: func(tb *testBreaker) {
+				defer func() {
+					if r := recover(); r != "boom" {
+						panic(fmt.Sprintf("recovered %v, want the probe's own panic", r))
+					}
+				}()
+				tb.Do(context.Background(), breakerKey, func(context.Context) error { panic("boom") })
+			},
+			want: BreakerOpen, // паника — неудача пробы
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tb := newTestBreaker(BreakerPolicy{MinRequests: 1, FailureRatio: 1, CoolDown: time.Second, HalfOpenProbes: 1})
+			tb.call(errDown)
+			tb.now = tb.now.Add(time.Second)
+			tb.wantState(t, BreakerHalfOpen)
+
+			tc.probe(tb)
+			tb.wantState(t, tc.want)
+
+			if tc.want == BreakerOpen {
+				wantCircuitOpen(t, tb.call(nil))
+				tb.now = tb.now.Add(time.Second)
+			}
+			// Слот свободен: следующая проба проходит и замыкает автомат.
+			if err := tb.call(nil); err != nil {
+				t.Fatalf("next probe: %v", err)
+			}
+			tb.wantState(t, BreakerClosed)
+		})
+	}
+}
+
+func TestBreakerIgnoresStaleResults(t *testing.T) {
+	tb := newTestBreaker(BreakerPolicy{MinRequests: 1, FailureRatio: 1, CoolDown: time.Second})
+
+	// Вызов начат в closed, а завершился после размыкания — его успех не замыкает автомат.
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- tb.Do(context.Background(), breakerKey, func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	tb.call(errDown)
+	tb.wantState(t, BreakerOpen)
+	close(release)
+	<-done
+	tb.wantState(t, BreakerOpen)
+}
+
+func TestBreakerKeysAreIndependent(t *testing.T) {
+	tb := newTestBreaker(BreakerPolicy{MinRequests: 1, FailureRatio: 1})
+	tb.call(errDown)
+	tb.wantState(t, BreakerOpen)
+	if got := tb.State("inventory/List"); got != BreakerClosed {
+		t.Fatalf("other key state = %v, want closed", got)
+	}
+	if err := tb.Do(context.Background(), "inventory/List", func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("other key: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package grpcx
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// HedgePolicy — когда отправлять вторую (страхующую) копию запроса. Нулевые поля — дефолты
+// (см. DefaultHedgePolicy).
+type HedgePolicy struct {
+	Percentile   float64       // копия уходит, если первая попытка дольше этого перцентиля недавних вызовов
+	MinDelay     time.Duration // задержка копии не меньше этого (защита от хеджа на «быстром» хвосте)
+	InitialDelay time.Duration // задержка, пока замеров меньше MinSamples
+	MinSamples   int
+	Window       int     // сколько последних замеров на ключ учитывать
+	MaxRatio     float64 // копий не больше этой доли от вызовов (0.1 — 10%)
+}
+
+// DefaultHedgePolicy — копия после p95 (не раньше 10ms, до 100 замеров — через 50ms),
+// окно 1000 замеров, не больше 10% копий.
+func DefaultHedgePolicy() HedgePolicy {
+	return HedgePolicy{
+		Percentile:   0.95,
+		MinDelay:     10 * time.Millisecond,
+		InitialDelay: 50 * time.Millisecond,
+		MinSamples:   100,
+		Window:       1000,
+		MaxRatio:     0.1,
+	}
+}
+
+func (p HedgePolicy) withDefaults() HedgePolicy {
+	d := DefaultHedgePolicy()
+	if p.Percentile <= 0 || p.Percentile >= 1 {
+		p.Percentile = d.Percentile
+	}
+	if p.MinDelay <= 0 {
+		p.MinDelay = d.MinDelay
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = d.InitialDelay
+	}
+	if p.MinSamples <= 0 {
+		p.MinSamples = d.MinSamples
+	}
+	if p.Window < p.MinSamples {
+		p.Window = max(d.Window, p.MinSamples)
+	}
+	if p.MaxRatio <= 0 {
+		p.MaxRatio = d.MaxRatio
+	}
+	return p
+}
+
+// Hedger считает задержки по ключам (обычно — полное имя метода) и держит общий
+// на все ключи бюджет копий. Горутинобезопасен.
+type Hedger struct {
+	policy HedgePolicy
+	budget *RetryBudget // тот же token bucket: вызов кладёт MaxRatio, копия забирает 1
+
+	mu    sync.Mutex
+	stats map[string]*latencies
+}
+
+type latencies struct {
+	ring  []time.Duration
+	next  int
+	fresh int           // замеров с последнего пересчёта delay
+	delay time.Duration // закэшированный перцентиль
+}
+
+func NewHedger(p HedgePolicy) *Hedger {
+	p = p.withDefaults()
+	return &Hedger{policy: p, budget: NewRetryBudget(p.MaxRatio, 0), stats: make(map[string]*latencies)}
+}
+
+// Delay — через сколько после начала вызова key уйдёт копия.
+func (h *Hedger) Delay(key string) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l := h.stats[key]
+	if l == nil || len(l.ring) < h.policy.MinSamples {
+		return h.policy.InitialDelay
+	}
+	// Сортировка окна на каждый вызов дорога: пересчёт раз в 5% окна.
+	if l.delay == 0 || l.fresh >= max(h.policy.Window/20, 1) {
+		sorted := slices.Clone(l.ring)
+		slices.Sort(sorted)
+		l.delay = sorted[int(h.policy.Percentile*float64(len(sorted)-1))]
+		l.fresh = 0
+	}
+	return max(l.delay, h.policy.MinDelay)
+}
+
+func (h *Hedger) observe(key string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l := h.stats[key]
+	if l == nil {
+		l = &latencies{}
+		h.stats[key] = l
+	}
+	if len(l.ring) < h.policy.Window {
+		l.ring = append(l.ring, d)
+	} else {
+		l.ring[l.next] = d
+		l.next = (l.next + 1) % h.policy.Window
+	}
+	l.fresh++
+}
+
+// Hedge вызывает fn; если ответа нет дольше h.Delay(key) и бюджет позволяет — запускает
+// вторую такую же попытку. Побеждает первый успешный ответ, проигравший отменяется.
+// Ошибка возвращается, только когда упали все запущенные попытки. h == nil — просто fn.
+//
+// fn должен быть идемпотентным: сервер может выполнить обе копии.
+func Hedge[T any](ctx context.Context, h *Hedger, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if h == nil {
+		return fn(ctx)
+	}
+	h.budget.deposit()
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // отменяет проигравшего
+
+	// Замер — от начала всего вызова до его исхода, успешного или нет: победившая копия
+	// без задержки хеджа и одни только успехи занижали бы перцентиль, и копий уходило бы
+	// больше MaxRatio. Вызов, брошенный самим вызывающим, ничего не говорит о сервере.
+	start := time.Now()
+	observe := func() {
+		if parent.Err() == nil {
+			h.observe(key, time.Since(start))
+		}
+	}
+
+	type result struct {
+		v   T
+		err error
+	}
+	results := make(chan result, 2)
+	launch := func() {
+		go func() {
+			v, err := fn(ctx)
+			results <- result{v: v, err: err}
+		}()
+	}
+
+	launch()
+	inflight := 1
+	timer := time.NewTimer(h.Delay(key))
+	defer timer.Stop()
+	hedgeC := timer.C
+	for {
+		select {
+		case <-hedgeC:
+			hedgeC = nil
+			if h.budget.withdraw() {
+				launch()
+				inflight++
+			}
+		case r := <-results:
+			inflight--
+			if r.err == nil {
+				observe()
+				return r.v, nil
+			}
+			if inflight == 0 {
+				observe()
+				return r.v, r.err
+			}
+			// Одна копия упала, другая ещё в пути — ждём её; новую не запускаем.
+			hedgeC = nil
+		}
+	}
+}
+
+// UnaryClientInterceptor — хедж для перечисленных (идемпотентных!) методов, ключ — имя метода.
+// Каждая копия пишет в свой экземпляр ответа, в reply копируется победитель.
+func (h *Hedger) UnaryClientInterceptor(methods ...string) grpc.UnaryClientInterceptor {
+	enabled := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		enabled[m] = struct{}{}
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		rm, ok := reply.(proto.Message)
+		if _, on := enabled[method]; !on || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		won, err := Hedge(ctx, h, method, func(ctx context.Context) (proto.Message, error) {
+			r := rm.ProtoReflect().New().Interface()
+			if err := invoker(ctx, method, req, r, cc, opts...); err != nil {
+				return nil, err
+			}
+			return r, nil
+		})
+		if err != nil {
+			return err
+		}
+		proto.Reset(rm)
+		proto.Merge(rm, won)
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package grpcx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// IdempotencyRecord — то, что сервер помнит про ключ.
+type IdempotencyRecord struct {
+	Fingerprint []byte     // sha256(метод + детерминированный proto запроса)
+	Response    *anypb.Any // nil — запрос ещё выполняется
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore — хранилище ключей идемпотентности (in-memory, Redis, БД...).
+type IdempotencyStore interface {
+	// Reserve атомарно занимает ключ на ttl. Если ключ уже занят и не истёк —
+	// возвращает его запись и reserved=false.
+	Reserve(ctx context.Context, key string, fingerprint []byte, ttl time.Duration) (rec IdempotencyRecord, reserved bool, err error)
+	// Complete сохраняет ответ для занятого ключа.
+	Complete(ctx context.Context, key string, resp *anypb.Any) error
+	// Release освобождает ключ (запрос упал — повтор с тем же ключом должен выполниться заново).
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyUnaryServerInterceptor — серверная идемпотентность для перечисленных методов
+// (полные имена, напр. invpb.StockAdminService_AdjustStock_FullMethodName).
+//
+//   - нет ключа в метаданных — запрос выполняется как обычно; ключ не UUID — INVALID_ARGUMENT;
+//   - повтор с тем же ключом и тем же запросом — возвращается сохранённый ответ, хендлер не вызывается;
+//   - тот же ключ с другим запросом — ALREADY_EXISTS;
+//   - тот же ключ, пока первый запрос ещё выполняется — UNAVAILABLE с RetryInfo и reason
+//     CodeIdempotencyInProgress (повторить позже тем же ключом).
+//
+// Сохраняются только успешные ответы; ошибка освобождает ключ. Ключи живут ttl.
+//
+// Подключён в inventory-svc (admin-RPC остатков). ItemsAdminService.CreateItem
+// (catpb.ItemsAdminService_CreateItem_FullMethodName) подключается так же, но у catalog-svc
+// пока нет gRPC-сервера — только исходящий адаптер к inventory.
+func IdempotencyUnaryServerInterceptor(store IdempotencyStore, ttl time.Duration, methods ...string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		enabled[m] = struct{}{}
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := enabled[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+		idemKey := incomingValue(ctx, MetadataIdempotencyKey)
+		if idemKey == "" {
+			return handler(ctx, req)
+		}
+		if err := ValidateMetadata(MetadataIdempotencyKey, idemKey); err != nil {
+			return nil, ToStatus(err).Err()
+		}
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		fp, err := fingerprint(info.FullMethod, msg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "idempotency fingerprint: %v", err)
+		}
+		// Ключи изолированы по методу: один и тот же uuid в разных RPC не конфликтует.
+		key := info.FullMethod + "|" + idemKey
+
+		rec, reserved, err := store.Reserve(ctx, key, fp, ttl)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "idempotency store: %v", err)
+		}
+		if !reserved {
+			switch {
+			case !bytes.Equal(rec.Fingerprint, fp):
+				return nil, status.Errorf(codes.AlreadyExists, "%s %q was already used with a different request", MetadataIdempotencyKey, idemKey)
+			case rec.Response == nil:
+				return nil, inProgress(idemKey)
+			default:
+				resp, err := rec.Response.UnmarshalNew()
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "idempotency replay: %v", err)
+				}
+				return resp, nil
+			}
+		}
+
+		resp, herr := handler(ctx, req)
+		if herr != nil {
+			_ = store.Release(context.WithoutCancel(ctx), key)
+			return nil, herr
+		}
+		if rm, ok := resp.(proto.Message); ok {
+			if a, err := anypb.New(rm); err == nil {
+				_ = store.Complete(context.WithoutCancel(ctx), key, a)
+				return resp, nil
+			}
+		}
+		// Ответ не сериализуется — лучше не запоминать, чем вернуть потом мусор.
+		_ = store.Release(context.WithoutCancel(ctx), key)
+		return resp, nil
+	}
+}
+
+// CodeIdempotencyInProgress — reason в ErrorInfo ответа «запрос с этим ключом ещё выполняется».
+// Это UNAVAILABLE с RetryInfo, а не ABORTED: ABORTED клиенты читают как конфликт версий
+// и перечитывают/применяют заново, хотя первая попытка ещё может закоммититься.
+const CodeIdempotencyInProgress = "IDEMPOTENCY_IN_PROGRESS"
+
+// inProgressRetryDelay — через сколько повторять запрос, пока первый ещё выполняется.
+const inProgressRetryDelay = 500 * time.Millisecond
+
+func inProgress(idemKey string) error {
+	err := errorsx.Wrap(errorsx.KindUnavailable, CodeIdempotencyInProgress, true, nil,
+		fmt.Errorf("request with %s %q is still in progress", MetadataIdempotencyKey, idemKey))
+	st := ToStatus(err)
+	if withRI, derr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(inProgressRetryDelay)}); derr == nil {
+		st = withRI
+	}
+	return st.Err()
+}
+
+func fingerprint(method string, msg proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(b)
+	return h.Sum(nil), nil
+}
+
+// ===== In-memory хранилище =====
+
+// MemoryIdempotencyStore — IdempotencyStore в памяти процесса (одна реплика / тесты).
+// Истёкшие ключи вычищаются лениво, не чаще раза в минуту.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	recs      map[string]IdempotencyRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{recs: make(map[string]IdempotencyRecord), now: time.Now}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, fingerprint []byte, ttl time.Duration) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweepLocked(now)
+	if rec, ok := s.recs[key]; ok && now.Before(rec.ExpiresAt) {
+		return rec, false, nil
+	}
+	rec := IdempotencyRecord{Fingerprint: fingerprint, ExpiresAt: now.Add(ttl)}
+	s.recs[key] = rec
+	return rec, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, resp *anypb.Any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.recs[key]; ok {
+		rec.Response = resp
+		s.recs[key] = rec
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recs, key)
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for k, rec := range s.recs {
+		if !now.Before(rec.ExpiresAt) {
+			delete(s.recs, k)
+		}
+	}
+}
@@ -1,3 +1,230 @@
 package grpcx
 
-//# лог/метрики/трейсинг, unary/server/client
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ===== Серверная цепочка =====
+
+// LatencyObserver — получает длительность каждого вызова и его итоговый код (метрики).
+type LatencyObserver func(method string, code codes.Code, d time.Duration)
+
+// ServerChainConfig — состав стандартной серверной цепочки (см. ServerChain).
+type ServerChainConfig struct {
+	Logger  *slog.Logger    // access-лог и паники; nil — slog.Default()
+	Observe LatencyObserver // nil — латентность только в access-логе
+
+	// Интерсепторы сервиса (идемпотентность и т.п.). Выполняются внутри стандартных:
+	// их ошибки и паники обрабатываются так же, как ошибки и паники хендлеров.
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// ServerChain — опции grpc.NewServer со стандартной цепочкой. Порядок снаружи внутрь:
+//
+//	request-id → access-лог → латентность → errorsx→status → recovery → проверка метаданных →
+//	cfg.Unary/Stream → хендлер
+//
+// Лог и метрики видят уже итоговый код: и доменную ошибку, и панику (INTERNAL).
+func ServerChain(cfg ServerChainConfig) []grpc.ServerOption {
+	unary := []grpc.UnaryServerInterceptor{
+		RequestIDUnaryServerInterceptor(),
+		AccessLogUnaryServerInterceptor(cfg.Logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		RequestIDStreamServerInterceptor(),
+		AccessLogStreamServerInterceptor(cfg.Logger),
+	}
+	if cfg.Observe != nil {
+		unary = append(unary, LatencyUnaryServerInterceptor(cfg.Observe))
+		stream = append(stream, LatencyStreamServerInterceptor(cfg.Observe))
+	}
+	unary = append(unary, ErrorsUnaryServerInterceptor(), RecoveryUnaryServerInterceptor(cfg.Logger),
+		MetadataUnaryServerInterceptor())
+	stream = append(stream, ErrorsStreamServerInterceptor(), RecoveryStreamServerInterceptor(cfg.Logger),
+		MetadataStreamServerInterceptor())
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(append(unary, cfg.Unary...)...),
+		grpc.ChainStreamInterceptor(append(stream, cfg.Stream...)...),
+	}
+}
+
+// serverStream — ServerStream с подменённым контекстом.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func loggerOrDefault(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
+
+// ===== Recovery =====
+
+// RecoveryUnaryServerInterceptor — паника в хендлере не роняет процесс: стек уходит в лог,
+// клиент получает INTERNAL без подробностей.
+func RecoveryUnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	logger = loggerOrDefault(logger)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, recovered(ctx, logger, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func RecoveryStreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	logger = loggerOrDefault(logger)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ss.Context(), logger, info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, logger *slog.Logger, method string, p any) error {
+	logger.LogAttrs(ctx, slog.LevelError, "grpc panic",
+		slog.String("method", method),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+		slog.Any("panic", p),
+		slog.String("stack", string(debug.Stack())),
+	)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// ===== Request ID =====
+
+// RequestIDUnaryServerInterceptor — гарантирует MetadataRequestID во входящих метаданных
+// (свой от клиента или новый UUID) и отдаёт его клиенту заголовком ответа.
+// Дальше по цепочке он доступен через RequestIDFromContext.
+func RequestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, id := ensureRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, id))
+		return handler(ctx, req)
+	}
+}
+
+func RequestIDStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := ensureRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(MetadataRequestID, id))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ensureRequestID оставляет в x-request-id ровно одно значение — то, что в ходу: лишние дубли
+// клиента отбрасываются, и проверка метаданных дальше по цепочке видит тот же ID, что и логи.
+func ensureRequestID(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	vs := md.Get(MetadataRequestID)
+	if len(vs) > 0 && validRequestID(vs[0]) {
+		if len(vs) > 1 {
+			ctx = withIncomingValue(ctx, MetadataRequestID, vs[0])
+		}
+		return ctx, vs[0]
+	}
+	id := NewUUID()
+	return withIncomingValue(ctx, MetadataRequestID, id), id
+}
+
+// ===== Access-лог и латентность =====
+
+// AccessLogUnaryServerInterceptor — одна структурированная запись на вызов: метод, код,
+// длительность, request_id, адрес клиента. Уровень по коду: OK — Info, ошибки клиента — Warn,
+// ошибки сервера (см. serverFault) — Error.
+func AccessLogUnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	logger = loggerOrDefault(logger)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logAccess(ctx, logger, "grpc unary", info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// AccessLogStreamServerInterceptor — запись пишется по завершении стрима.
+func AccessLogStreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	logger = loggerOrDefault(logger)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logAccess(ss.Context(), logger, "grpc stream", info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func logAccess(ctx context.Context, logger *slog.Logger, msg, method string, err error, d time.Duration) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch {
+	case serverFault(code):
+		level = slog.LevelError
+	case code != codes.OK:
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", d),
+		slog.String("request_id", RequestIDFromContext(ctx)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// serverFault — коды, означающие проблему сервера, а не запроса.
+func serverFault(c codes.Code) bool {
+	switch c {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented, codes.Unavailable:
+		return true
+	}
+	return false
+}
+
+// LatencyUnaryServerInterceptor — отдаёт длительность и итоговый код вызова в observe.
+func LatencyUnaryServerInterceptor(observe LatencyObserver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+func LatencyStreamServerInterceptor(observe LatencyObserver) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
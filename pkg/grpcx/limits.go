@@ -1,3 +1,204 @@
 package grpcx
 
-//# MaxRecv/Send size, keepalive
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Limits — пределы транспорта одного сервера: размеры сообщений, потоки, keepalive и возраст
+// соединений. Нулевое поле — дефолт gRPC (для возраста/простоя соединения — без ограничения);
+// рекомендуемые значения — DefaultLimits.
+type Limits struct {
+	MaxRecvMsgSize       int    // байт в одном входящем сообщении сервера (BatchAdjustStock на 500 строк — ~100KB)
+	MaxSendMsgSize       int    // байт в одном исходящем сообщении сервера
+	MaxConcurrentStreams uint32 // одновременных вызовов на соединение (WatchStock держит поток)
+
+	KeepaliveTime    time.Duration // пинг соединения без трафика; не меньше 10s (минимум клиента gRPC)
+	KeepaliveTimeout time.Duration // ожидание ответа на пинг, дальше соединение рвётся
+
+	MaxConnectionIdle     time.Duration // закрыть соединение без вызовов
+	MaxConnectionAge      time.Duration // GOAWAY по возрасту: клиенты перебалансируются по репликам
+	MaxConnectionAgeGrace time.Duration // сколько ждать незавершённые вызовы после GOAWAY
+
+	// Enforcement: клиент, пингующий чаще MinPingInterval (или без активных вызовов при
+	// PermitWithoutStream=false), получает GOAWAY too_many_pings.
+	MinPingInterval     time.Duration
+	PermitWithoutStream bool
+}
+
+const maxMsgSizeLimit = 256 << 20 // больше — почти наверняка ошибка в единицах
+
+// DefaultLimits — 8MiB на вход, 16MiB на выход, 1000 потоков на соединение; пинг простаивающих
+// соединений раз в 30s с таймаутом 10s; соединение живёт до 30m (+1m на завершение вызовов),
+// без вызовов — до 15m; клиентам можно пинговать не чаще раза в 10s и без активных вызовов.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxRecvMsgSize:        8 << 20,
+		MaxSendMsgSize:        16 << 20,
+		MaxConcurrentStreams:  1000,
+		KeepaliveTime:         30 * time.Second,
+		KeepaliveTimeout:      10 * time.Second,
+		MaxConnectionIdle:     15 * time.Minute,
+		MaxConnectionAge:      30 * time.Minute,
+		MaxConnectionAgeGrace: time.Minute,
+		MinPingInterval:       10 * time.Second,
+		PermitWithoutStream:   true,
+	}
+}
+
+// Validate — все нарушения разом (errors.Join).
+func (l Limits) Validate() error {
+	var errs []error
+	bad := func(format string, a ...any) { errs = append(errs, fmt.Errorf("grpcx: limits: "+format, a...)) }
+
+	for _, f := range []struct {
+		name string
+		v    int
+	}{{"max_recv_msg_size", l.MaxRecvMsgSize}, {"max_send_msg_size", l.MaxSendMsgSize}} {
+		if f.v < 0 || f.v > maxMsgSizeLimit {
+			bad("%s must be in [0, %d], got %d", f.name, maxMsgSizeLimit, f.v)
+		}
+	}
+	for _, f := range []struct {
+		name string
+		v    time.Duration
+	}{
+		{"keepalive_time", l.KeepaliveTime},
+		{"keepalive_timeout", l.KeepaliveTimeout},
+		{"max_connection_idle", l.MaxConnectionIdle},
+		{"max_connection_age", l.MaxConnectionAge},
+		{"max_connection_age_grace", l.MaxConnectionAgeGrace},
+		{"min_ping_interval", l.MinPingInterval},
+	} {
+		if f.v < 0 {
+			bad("%s must not be negative, got %s", f.name, f.v)
+		}
+	}
+	if l.KeepaliveTime > 0 && l.KeepaliveTime < 10*time.Second {
+		bad("keepalive_time must be at least 10s, got %s", l.KeepaliveTime)
+	}
+	if l.KeepaliveTime > 0 && l.MinPingInterval > l.KeepaliveTime {
+		// Клиенты с ClientOptions пинговали бы чаще, чем сервер разрешает, и получали GOAWAY.
+		bad("min_ping_interval (%s) must not exceed keepalive_time (%s)", l.MinPingInterval, l.KeepaliveTime)
+	}
+	if l.MaxConnectionAgeGrace > 0 && l.MaxConnectionAge == 0 {
+		bad("max_connection_age_grace is set without max_connection_age")
+	}
+	return errors.Join(errs...)
+}
+
+// ServerOptions — опции grpc.NewServer под эти пределы.
+func (l Limits) ServerOptions() ([]grpc.ServerOption, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	var opts []grpc.ServerOption
+	if l.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(l.MaxRecvMsgSize))
+	}
+	if l.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(l.MaxSendMsgSize))
+	}
+	if l.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(l.MaxConcurrentStreams))
+	}
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     l.MaxConnectionIdle,
+			MaxConnectionAge:      l.MaxConnectionAge,
+			MaxConnectionAgeGrace: l.MaxConnectionAgeGrace,
+			Time:                  l.KeepaliveTime,
+			Timeout:               l.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             l.MinPingInterval,
+			PermitWithoutStream: l.PermitWithoutStream,
+		}),
+	)
+	return opts, nil
+}
+
+// ClientOptions — опции соединения клиента к серверу с этими пределами: клиент принимает
+// сообщения до MaxSendMsgSize сервера, отправляет до его MaxRecvMsgSize (больше сервер
+// всё равно отклонит) и пингует так, чтобы не нарушить enforcement.
+func (l Limits) ClientOptions() ([]grpc.DialOption, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	var call []grpc.CallOption
+	if l.MaxSendMsgSize > 0 {
+		call = append(call, grpc.MaxCallRecvMsgSize(l.MaxSendMsgSize))
+	}
+	if l.MaxRecvMsgSize > 0 {
+		call = append(call, grpc.MaxCallSendMsgSize(l.MaxRecvMsgSize))
+	}
+	var opts []grpc.DialOption
+	if len(call) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(call...))
+	}
+	if l.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                l.KeepaliveTime,
+			Timeout:             l.KeepaliveTimeout,
+			PermitWithoutStream: l.PermitWithoutStream,
+		}))
+	}
+	return opts, nil
+}
+
+// LimitsFromEnv — base, переопределённый переменными окружения с префиксом prefix
+// (например "INVENTORY_GRPC_"):
+//
+//	MAX_RECV_MSG_SIZE, MAX_SEND_MSG_SIZE   — байты
+//	MAX_CONCURRENT_STREAMS                 — число
+//	KEEPALIVE_TIME, KEEPALIVE_TIMEOUT      — duration ("30s")
+//	MAX_CONNECTION_IDLE, MAX_CONNECTION_AGE, MAX_CONNECTION_AGE_GRACE, MIN_PING_INTERVAL — duration
+//	PERMIT_WITHOUT_STREAM                  — bool
+//
+// Результат проверяется Validate.
+func LimitsFromEnv(prefix string, base Limits) (Limits, error) {
+	l := base
+	var errs []error
+	env := func(name string, parse func(string) error) {
+		if v := os.Getenv(prefix + name); v != "" {
+			if err := parse(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s%s: %w", prefix, name, err))
+			}
+		}
+	}
+	size := func(dst *int) func(string) error {
+		return func(v string) (err error) { *dst, err = strconv.Atoi(v); return err }
+	}
+	dur := func(dst *time.Duration) func(string) error {
+		return func(v string) (err error) { *dst, err = time.ParseDuration(v); return err }
+	}
+
+	env("MAX_RECV_MSG_SIZE", size(&l.MaxRecvMsgSize))
+	env("MAX_SEND_MSG_SIZE", size(&l.MaxSendMsgSize))
+	env("MAX_CONCURRENT_STREAMS", func(v string) error {
+		n, err := strconv.ParseUint(v, 10, 32)
+		l.MaxConcurrentStreams = uint32(n)
+		return err
+	})
+	env("KEEPALIVE_TIME", dur(&l.KeepaliveTime))
+	env("KEEPALIVE_TIMEOUT", dur(&l.KeepaliveTimeout))
+	env("MAX_CONNECTION_IDLE", dur(&l.MaxConnectionIdle))
+	env("MAX_CONNECTION_AGE", dur(&l.MaxConnectionAge))
+	env("MAX_CONNECTION_AGE_GRACE", dur(&l.MaxConnectionAgeGrace))
+	env("MIN_PING_INTERVAL", dur(&l.MinPingInterval))
+	env("PERMIT_WITHOUT_STREAM", func(v string) (err error) { l.PermitWithoutStream, err = strconv.ParseBool(v); return err })
+
+	if err := errors.Join(errs...); err != nil {
+		return base, err
+	}
+	if err := l.Validate(); err != nil {
+		return base, err
+	}
+	return l, nil
+}
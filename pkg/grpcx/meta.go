@@ -1,3 +1,345 @@
 package grpcx
 
-//# Get/Set метаданных (idempotency-key и т.п.)
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// ===== Ключи метаданных =====
+
+const (
+	// MetadataIdempotencyKey — ключ идемпотентности admin-операции, UUID (см. stock_admin.proto).
+	MetadataIdempotencyKey = "idempotency-key"
+	// MetadataRequestID — сквозной ID запроса. Клиент может прислать свой; если нет (или он
+	// невалиден) — сервер назначает новый и возвращает его в заголовке ответа.
+	MetadataRequestID = "x-request-id"
+	// MetadataTenant — арендатор (магазин/витрина): [A-Za-z0-9._-], до 64 символов.
+	MetadataTenant = "x-tenant-id"
+	// MetadataActor — кто выполняет операцию (логин/сервис), пишется в журналы.
+	MetadataActor = "x-actor"
+	// MetadataLocale — язык ответа в форме BCP 47: "ru", "en-US", "zh-Hant-TW".
+	MetadataLocale = "x-locale"
+)
+
+// DefaultPropagatedMetadata — заголовки, которые PropagateMetadata переносит из входящего
+// вызова в исходящие. Ключа идемпотентности здесь нет: у каждой исходящей операции он свой.
+func DefaultPropagatedMetadata() []string {
+	return []string{MetadataRequestID, MetadataTenant, MetadataActor, MetadataLocale}
+}
+
+const (
+	maxRequestIDLen = 128
+	maxTenantLen    = 64
+	maxActorLen     = 128
+	maxLocaleLen    = 35
+)
+
+// metaValidators — форматы известных заголовков; прочие ключи не проверяются.
+var metaValidators = map[string]func(string) bool{
+	MetadataIdempotencyKey: isUUID,
+	MetadataRequestID:      validRequestID,
+	MetadataTenant:         validTenant,
+	MetadataActor:          validActor,
+	MetadataLocale:         validLocale,
+}
+
+// ValidateMetadata — проверка значения заголовка key; для неизвестных ключей всегда nil.
+// Ошибка — *errorsx.ValidationError с полем "metadata.<key>".
+func ValidateMetadata(key, value string) error {
+	if valid, ok := metaValidators[key]; ok && !valid(value) {
+		return errorsx.NewValidation().Add("metadata."+key, "INVALID_FORMAT",
+			fmt.Sprintf("malformed %s: %q", key, truncate(value, 64)), nil)
+	}
+	return nil
+}
+
+// ===== Входящие (сервер) =====
+// Геттеры возвращают "" и для отсутствующего, и для невалидного значения; отклонить
+// невалидные заголовки с INVALID_ARGUMENT — дело MetadataUnaryServerInterceptor.
+
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	return validIncoming(ctx, MetadataIdempotencyKey)
+}
+
+// RequestIDFromContext — ID запроса; после RequestIDUnaryServerInterceptor в хендлере он есть всегда.
+func RequestIDFromContext(ctx context.Context) string {
+	return validIncoming(ctx, MetadataRequestID)
+}
+
+func TenantFromContext(ctx context.Context) string { return validIncoming(ctx, MetadataTenant) }
+func ActorFromContext(ctx context.Context) string  { return validIncoming(ctx, MetadataActor) }
+func LocaleFromContext(ctx context.Context) string { return validIncoming(ctx, MetadataLocale) }
+
+func validIncoming(ctx context.Context, key string) string {
+	v := incomingValue(ctx, key)
+	if ValidateMetadata(key, v) != nil {
+		return ""
+	}
+	return v
+}
+
+// ===== Исходящие (клиент) =====
+// Сеттеры заменяют уже заданное в исходящих метаданных значение; невалидное не ставится.
+
+func WithIdempotencyKey(ctx context.Context, key string) (context.Context, error) {
+	return withOutgoing(ctx, MetadataIdempotencyKey, key)
+}
+
+func WithRequestID(ctx context.Context, id string) (context.Context, error) {
+	return withOutgoing(ctx, MetadataRequestID, id)
+}
+
+func WithTenant(ctx context.Context, tenant string) (context.Context, error) {
+	return withOutgoing(ctx, MetadataTenant, tenant)
+}
+
+func WithActor(ctx context.Context, actor string) (context.Context, error) {
+	return withOutgoing(ctx, MetadataActor, actor)
+}
+
+func WithLocale(ctx context.Context, locale string) (context.Context, error) {
+	return withOutgoing(ctx, MetadataLocale, locale)
+}
+
+func withOutgoing(ctx context.Context, key, value string) (context.Context, error) {
+	if err := ValidateMetadata(key, value); err != nil {
+		return ctx, err
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(key, value)
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+// IdempotencyKeyFor — детерминированный ключ идемпотентности (UUID v5) из произвольного имени,
+// например ID строки импорта: повтор того же импорта даёт тот же ключ.
+func IdempotencyKeyFor(name string) string {
+	h := sha1.New()
+	h.Write(idempotencyNamespace[:])
+	h.Write([]byte(name))
+	var b [16]byte
+	copy(b[:], h.Sum(nil))
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+// idempotencyNamespace — пространство имён UUID v5 для IdempotencyKeyFor.
+var idempotencyNamespace = [16]byte{0x3b, 0x0e, 0x6f, 0x2a, 0x51, 0x8d, 0x4c, 0x17, 0x9a, 0x64, 0x0d, 0xe2, 0x75, 0xc1, 0x48, 0x93}
+
+// ===== Проброс из входящего вызова в исходящие =====
+
+// PropagateMetadata — копирует заголовки keys (по умолчанию DefaultPropagatedMetadata) из входящих
+// метаданных ctx в исходящие. Явно заданные в исходящих значения и невалидные входящие не трогает.
+// Нужен там, где сервер в хендлере сам ходит в другие сервисы с тем же ctx.
+func PropagateMetadata(ctx context.Context, keys ...string) context.Context {
+	in, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if len(keys) == 0 {
+		keys = DefaultPropagatedMetadata()
+	}
+	out, _ := metadata.FromOutgoingContext(ctx)
+	var kv []string
+	for _, k := range keys {
+		v := in.Get(k)
+		if len(v) == 0 || len(out.Get(k)) > 0 || ValidateMetadata(k, v[0]) != nil {
+			continue
+		}
+		kv = append(kv, k, v[0])
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// PropagateUnaryClientInterceptor — PropagateMetadata(ctx, keys...) для каждого исходящего вызова.
+func PropagateUnaryClientInterceptor(keys ...string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(PropagateMetadata(ctx, keys...), method, req, reply, cc, opts...)
+	}
+}
+
+func PropagateStreamClientInterceptor(keys ...string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(PropagateMetadata(ctx, keys...), desc, cc, method, opts...)
+	}
+}
+
+// ===== Проверка на сервере =====
+
+// MetadataUnaryServerInterceptor — невалидные известные заголовки (см. ValidateMetadata)
+// отклоняются INVALID_ARGUMENT до хендлера. Request-ID сюда не доходит невалидным:
+// его заменяет RequestIDUnaryServerInterceptor.
+func MetadataUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validateIncoming(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func MetadataStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := validateIncoming(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// validateIncoming — все нарушения разом, в фиксированном порядке ключей.
+func validateIncoming(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	var ve *errorsx.ValidationError
+	for _, k := range []string{MetadataIdempotencyKey, MetadataRequestID, MetadataTenant, MetadataActor, MetadataLocale} {
+		for _, v := range md.Get(k) {
+			if err := ValidateMetadata(k, v); err != nil {
+				ve = ve.Merge(err.(*errorsx.ValidationError))
+			}
+		}
+	}
+	if ve == nil {
+		return nil
+	}
+	return ve
+}
+
+// ===== Форматы =====
+
+// isUUID — каноническая форма 8-4-4-4-12 (регистр hex-цифр любой).
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validRequestID — непустой, не длиннее maxRequestIDLen, только печатный ASCII без пробелов
+// (ID уходит в логи и заголовки — переводы строк и управляющие символы недопустимы).
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func validTenant(s string) bool {
+	if s == "" || len(s) > maxTenantLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(isAlnum(c) || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// validActor — печатный ASCII (пробелы внутри допустимы), без пробелов по краям.
+func validActor(s string) bool {
+	if s == "" || len(s) > maxActorLen || strings.TrimSpace(s) != s {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// validLocale — форма BCP 47 без проверки по реестру: язык из 2–3 букв, дальше
+// подтеги из 1–8 букв/цифр через '-' ("en", "en-US", "sr-Latn-RS").
+func validLocale(s string) bool {
+	if s == "" || len(s) > maxLocaleLen {
+		return false
+	}
+	for i, tag := range strings.Split(s, "-") {
+		if i == 0 && (len(tag) < 2 || len(tag) > 3) || len(tag) < 1 || len(tag) > 8 {
+			return false
+		}
+		for j := 0; j < len(tag); j++ {
+			if i == 0 && !isAlpha(tag[j]) || !isAlnum(tag[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isAlpha(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+func isAlnum(c byte) bool { return isAlpha(c) || '0' <= c && c <= '9' }
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
+
+// NewUUID — случайный UUID v4: request-id, ключи идемпотентности, ID сущностей сервисов.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// incomingValue — первое значение key во входящих метаданных.
+func incomingValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// withIncomingValue — ctx, во входящих метаданных которого key = value (исходные md не меняются).
+func withIncomingValue(ctx context.Context, key, value string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(key, value)
+	return metadata.NewIncomingContext(ctx, md)
+}
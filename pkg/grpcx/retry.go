@@ -1,3 +1,289 @@
 package grpcx
 
-// # политика ретраев
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// ===== Политика =====
+
+// RetryPolicy — когда и как повторять unary-вызовы. Нулевые поля заменяются дефолтами
+// (см. DefaultRetryPolicy), кроме PerAttemptTimeout и Budget: 0/nil — выключено.
+type RetryPolicy struct {
+	MaxAttempts       int           // всего попыток, включая первую; 1 — без повторов
+	InitialBackoff    time.Duration // пауза перед первым повтором
+	MaxBackoff        time.Duration // потолок паузы
+	Multiplier        float64       // рост паузы от попытки к попытке
+	Jitter            float64       // 0..1: пауза случайно уменьшается до этой доли (0.2 — на 0..20%)
+	PerAttemptTimeout time.Duration // дедлайн одной попытки; общий задаёт ctx вызывающего
+	Budget            *RetryBudget  // общий на клиента бюджет повторов; nil — без ограничения
+
+	// Retryable решает, повторять ли ошибку попытки (gRPC status error).
+	// nil — классификация errorsx.RetryableOf по коду (UNAVAILABLE, RESOURCE_EXHAUSTED)
+	// плюс DEADLINE_EXCEEDED попытки. RetryInfo от сервера только удлиняет паузу: повторять
+	// или нет, решает Retryable (подсказка сервера не отменяет политику вызывающего).
+	// С CircuitBreakerUnaryClientInterceptor ставить ретраи снаружи (раньше в цепочке):
+	// каждая попытка проходит через автомат, а открытый автомат повтор останавливает.
+	Retryable func(err error) bool
+	// SkipMethods — полные имена неидемпотентных методов, которые интерсептор не повторяет.
+	SkipMethods []string
+}
+
+// DefaultRetryPolicy — 3 попытки, 100ms → 2s, x2, jitter 20%.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	d := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = d.InitialBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = max(d.MaxBackoff, p.InitialBackoff)
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = d.Multiplier
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	if p.Retryable == nil {
+		p.Retryable = defaultRetryable
+	}
+	return p
+}
+
+// Backoff — пауза перед повтором номер attempt (1 — первый повтор), с jitter.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	p = p.withDefaults()
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(max(attempt, 1)-1))
+	if d > float64(p.MaxBackoff) || math.IsInf(d, 0) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// defaultRetryable — статус раскладывается обратно в errorsx (FromStatus), решает errorsx.RetryableOf:
+// флаг retryable из ErrorInfo сервера главнее класса по коду.
+// DEADLINE_EXCEEDED здесь — таймаут попытки: дедлайн ctx вызывающего проверяется раньше.
+// Локальные ошибки с классом errorsx (например, CircuitOpenError) решают сами.
+func defaultRetryable(err error) bool {
+	if _, ok := errorsx.AsE(err); ok {
+		return errorsx.RetryableOf(err)
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.DeadlineExceeded {
+		return true
+	}
+	return errorsx.RetryableOf(FromStatus(st))
+}
+
+// classOfCode — корневой сентинел errorsx для gRPC-кода (nil — класса нет).
+func classOfCode(c codes.Code) error {
+	switch c {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return errorsx.ErrInvalidArgument
+	case codes.NotFound:
+		return errorsx.ErrNotFound
+	case codes.AlreadyExists:
+		return errorsx.ErrAlreadyExists
+	case codes.Aborted:
+		return errorsx.ErrAborted
+	case codes.FailedPrecondition:
+		return errorsx.ErrFailedPrecondition
+	case codes.Unauthenticated:
+		return errorsx.ErrUnauthenticated
+	case codes.PermissionDenied:
+		return errorsx.ErrPermissionDenied
+	case codes.ResourceExhausted:
+		return errorsx.ErrResourceExhausted
+	case codes.Unavailable:
+		return errorsx.ErrUnavailable
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		return errorsx.ErrInternal
+	default:
+		return nil
+	}
+}
+
+// retryInfoDelay — google.rpc.RetryInfo из деталей ошибки: сервер сам говорит, через сколько повторить.
+func retryInfoDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			return max(ri.GetRetryDelay().AsDuration(), 0), true
+		}
+	}
+	return 0, false
+}
+
+// ===== Бюджет =====
+
+// RetryBudget — защита от шторма повторов: каждый вызов кладёт ratio токена, каждый повтор
+// забирает один. Когда бэкенд лежит, повторов не больше ratio от трафика; minPerSecond
+// повторов в секунду доступны всегда — иначе при редких вызовах ретраев бы не было.
+// Один бюджет делится всеми вызовами клиента (горутинобезопасен).
+type RetryBudget struct {
+	ratio     float64
+	perSecond float64
+	capacity  float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRetryBudget — ratio 0.1 значит «повторов не больше 10% от вызовов».
+func NewRetryBudget(ratio float64, minPerSecond int) *RetryBudget {
+	ratio = max(ratio, 0)
+	perSecond := float64(max(minPerSecond, 0))
+	// Запас — 10 секунд minPerSecond, но хотя бы один повтор.
+	capacity := max(perSecond*10, 1)
+	return &RetryBudget{ratio: ratio, perSecond: perSecond, capacity: capacity, tokens: capacity, now: time.Now}
+}
+
+// deposit — учесть вызов (первую попытку).
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked()
+	b.tokens = min(b.tokens+b.ratio, b.capacity)
+}
+
+// withdraw — можно ли сделать ещё один повтор.
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *RetryBudget) refillLocked() {
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.perSecond, b.capacity)
+	}
+	b.last = now
+}
+
+// ===== Исполнитель =====
+
+// Retrier выполняет вызовы по RetryPolicy. Один Retrier — один бюджет на все вызовы.
+type Retrier struct {
+	policy RetryPolicy
+	skip   map[string]struct{}
+}
+
+func NewRetrier(p RetryPolicy) *Retrier {
+	p = p.withDefaults()
+	skip := make(map[string]struct{}, len(p.SkipMethods))
+	for _, m := range p.SkipMethods {
+		skip[m] = struct{}{}
+	}
+	return &Retrier{policy: p, skip: skip}
+}
+
+// Policy — политика с применёнными дефолтами.
+func (r *Retrier) Policy() RetryPolicy { return r.policy }
+
+// Do вызывает fn, пока он не вернёт nil, неповторяемую ошибку или не кончатся попытки/бюджет.
+// Возвращает ошибку последней попытки как есть (gRPC status error); если ctx истёк
+// во время паузы — status с кодом ctx (CANCELED / DEADLINE_EXCEEDED).
+func (r *Retrier) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	p := r.policy
+	p.Budget.deposit()
+	for attempt := 1; ; attempt++ {
+		err := r.attempt(ctx, fn)
+		if err == nil || ctx.Err() != nil || attempt >= p.MaxAttempts {
+			return err
+		}
+
+		if !p.Retryable(err) {
+			return err
+		}
+		delay := p.Backoff(attempt)
+		if hint, ok := retryInfoDelay(err); ok {
+			delay = max(delay, hint)
+		}
+		// Не ждать паузу, после которой на попытку уже не останется времени.
+		if deadline, has := ctx.Deadline(); has && time.Until(deadline) <= delay {
+			return err
+		}
+		if !p.Budget.withdraw() {
+			return err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (r *Retrier) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.policy.PerAttemptTimeout <= 0 {
+		return fn(ctx)
+	}
+	actx, cancel := context.WithTimeout(ctx, r.policy.PerAttemptTimeout)
+	defer cancel()
+	return fn(actx)
+}
+
+// UnaryClientInterceptor — ретраи для всех unary-методов соединения, кроме SkipMethods.
+//
+//	conn, _ := grpc.NewClient(target, grpc.WithChainUnaryInterceptor(grpcx.NewRetrier(p).UnaryClientInterceptor()))
+func (r *Retrier) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := r.skip[method]; ok {
+			return r.attempt(ctx, func(ctx context.Context) error {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			})
+		}
+		return r.Do(ctx, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// RetryUnaryClientInterceptor — то же, что NewRetrier(p).UnaryClientInterceptor().
+func RetryUnaryClientInterceptor(p RetryPolicy) grpc.UnaryClientInterceptor {
+	return NewRetrier(p).UnaryClientInterceptor()
+}
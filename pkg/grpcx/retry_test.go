@@ -0,0 +1,197 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// withRetryInfo — статус err с подсказкой сервера «повторить через d».
+func withRetryInfo(t *testing.T, err error, d time.Duration) error {
+	t.Helper()
+	st, derr := ToStatus(err).WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	if derr != nil {
+		t.Fatal(derr)
+	}
+	return st.Err()
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "slow down"), true},
+		{"attempt deadline", status.Error(codes.DeadlineExceeded, "attempt timeout"), true},
+		{"not found", status.Error(codes.NotFound, "nope"), false},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad"), false},
+		{"aborted", status.Error(codes.Aborted, "version"), false},
+		{"failed precondition", status.Error(codes.FailedPrecondition, "negative"), false},
+		{"internal", status.Error(codes.Internal, "boom"), false},
+		{"unknown", status.Error(codes.Unknown, "?"), false},
+		{"canceled", status.Error(codes.Canceled, "gone"), false},
+		{"plain error", errors.New("local"), false},
+
+		// Флаг retryable из ErrorInfo сервера главнее класса по коду.
+		{"server says retryable", ToStatus(errorsx.Wrap(errorsx.KindFailedPrecondition, "WARMING_UP", true, nil, nil)).Err(), true},
+		{"server says not retryable", ToStatus(errorsx.Wrap(errorsx.KindUnavailable, "MAINTENANCE", false, nil, nil)).Err(), false},
+		{"sentinel unavailable", ToStatus(errorsx.Unavailable("db down")).Err(), true},
+		{"sentinel not found", ToStatus(errorsx.NotFound("item 1")).Err(), false},
+
+		// Локальные ошибки errorsx решают сами.
+		{"local retryable E", errorsx.Wrap(errorsx.KindInternal, "FLAKY", true, nil, nil), true},
+		{"circuit open", circuitOpen("k"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := defaultRetryable(tc.err); got != tc.want {
+				t.Fatalf("defaultRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetrierDo(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "down")
+	for _, tc := range []struct {
+		name      string
+		policy    RetryPolicy
+		errs      []error // ошибка i-й попытки; дальше — nil
+		wantCalls int
+		wantErr   bool
+		minTook   time.Duration
+	}{
+		{
+			name:      "success first try",
+			wantCalls: 1,
+		},
+		{
+			name:      "retryable until success",
+			errs:      []error{unavailable, unavailable},
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exhausted",
+			errs:      []error{unavailable, unavailable, unavailable, unavailable},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "not retryable",
+			errs:      []error{status.Error(codes.NotFound, "nope")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retry info stretches the pause",
+			errs:      []error{withRetryInfo(t, errorsx.Wrap(errorsx.KindUnavailable, "BUSY", true, nil, nil), 50*time.Millisecond)},
+			wantCalls: 2,
+			minTook:   50 * time.Millisecond,
+		},
+		{
+			name:      "retry info does not override the policy",
+			policy:    RetryPolicy{Retryable: func(error) bool { return false }},
+			errs:      []error{withRetryInfo(t, errorsx.Wrap(errorsx.KindUnavailable, "BUSY", true, nil, nil), time.Millisecond)},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retry info on a non-retryable error",
+			errs:      []error{withRetryInfo(t, errorsx.Wrap(errorsx.KindFailedPrecondition, "NOPE", false, nil, nil), time.Millisecond)},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "single attempt",
+			policy:    RetryPolicy{MaxAttempts: 1},
+			errs:      []error{unavailable},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "budget exhausted",
+			policy:    RetryPolicy{MaxAttempts: 5, Budget: NewRetryBudget(0, 0)}, // запас — один повтор
+			errs:      []error{unavailable, unavailable, unavailable},
+			wantCalls: 2,
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.policy
+			p.InitialBackoff, p.MaxBackoff, p.Jitter = time.Millisecond, time.Millisecond, 0
+			calls := 0
+			start := time.Now()
+			err := NewRetrier(p).Do(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			})
+			if calls != tc.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tc.wantCalls)
+			}
+			if (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, want error: %v", err, tc.wantErr)
+			}
+			if took := time.Since(start); took < tc.minTook {
+				t.Errorf("took %v, want at least %v", took, tc.minTook)
+			}
+		})
+	}
+}
+
+func TestRetrierDoStopsBeforeDeadline(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	calls := 0
+	start := time.Now()
+	err := NewRetrier(p).Do(ctx, func(ctx context.Context) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	})
+	if calls != 1 || status.Code(err) != codes.Unavailable {
+		t.Fatalf("calls = %d, err = %v; want 1 call and the attempt's UNAVAILABLE", calls, err)
+	}
+	if took := time.Since(start); took > 50*time.Millisecond {
+		t.Fatalf("waited %v for a pause that cannot fit before the deadline", took)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewRetryBudget(0.5, 1) // запас 10 повторов, +1/с, +0.5 за вызов
+	b.now = func() time.Time { return now }
+	for i := range 10 {
+		if !b.withdraw() {
+			t.Fatalf("withdraw %d: budget empty too early", i)
+		}
+	}
+	if b.withdraw() {
+		t.Fatal("withdraw: want empty budget")
+	}
+	b.deposit()
+	b.deposit()
+	if !b.withdraw() || b.withdraw() {
+		t.Fatal("two calls at ratio 0.5 should buy exactly one retry")
+	}
+	now = now.Add(3 * time.Second)
+	for i := range 3 {
+		if !b.withdraw() {
+			t.Fatalf("withdraw %d after refill: budget empty", i)
+		}
+	}
+	if b.withdraw() {
+		t.Fatal("refill should give exactly minPerSecond per second")
+	}
+}
@@ -0,0 +1,379 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+// ErrorDomain — google.rpc.ErrorInfo.domain ошибок, собранных ToStatus.
+const ErrorDomain = "errorsx.ecommerce"
+
+// Ключи ErrorInfo.metadata.
+const (
+	metaKind       = "kind"
+	metaRetryable  = "retryable"
+	metaValidation = "validation" // "true" — исходная ошибка *errorsx.ValidationError, а не *errorsx.E
+	metaSentinel   = "sentinel"   // "true" — обёртка сентинела (errorsx.NotFoundf и т.п.), а не *errorsx.E
+	metaParam      = "param."     // param.<i>.<name> — Params i-го нарушения (в BadRequest им места нет);
+	// param.<i> без имени — Params есть, но пуст
+)
+
+// ===== errorsx -> status =====
+
+// ToStatus — gRPC-статус для ошибки любого вида:
+//
+//   - *errorsx.E: код по Kind; Code, Kind и Retryable — в google.rpc.ErrorInfo (reason = Code),
+//     Violations — в google.rpc.BadRequest (reason = Code нарушения, description = Message);
+//   - *errorsx.ValidationError: INVALID_ARGUMENT + BadRequest + ErrorInfo;
+//   - ошибка, которая уже несёт статус (status.Error и т.п.), — как есть;
+//   - обёртки errorsx.NotFoundf и т.п.: код по сентинелу, сообщение — err.Error(), Kind — в ErrorInfo
+//     (CONFLICT и ABORTED делят код ABORTED);
+//   - ctx.Err() — CANCELED / DEADLINE_EXCEEDED; всё остальное — INTERNAL.
+//
+// FromStatus восстанавливает из результата исходную ошибку (для *errorsx.E — равную).
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if e, ok := errorsx.AsE(err); ok {
+		st := status.New(codeForKind(e.Kind), e.Err.Error())
+		info := &errdetails.ErrorInfo{
+			Reason: e.Code,
+			Domain: ErrorDomain,
+			Metadata: map[string]string{
+				metaKind:      string(e.Kind),
+				metaRetryable: strconv.FormatBool(e.Retryable),
+			},
+		}
+		return withDetails(st, info, e.Violations)
+	}
+	if ve, ok := errorsx.AsValidation(err); ok {
+		st := status.New(codes.InvalidArgument, err.Error())
+		return withDetails(st, validationInfo(), ve.Violations())
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+	if k := errorsx.KindOf(err); k != "" {
+		info := &errdetails.ErrorInfo{
+			Reason:   string(k),
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{metaKind: string(k), metaSentinel: "true"},
+		}
+		return withDetails(status.New(codeForKind(k), err.Error()), info, nil)
+	}
+	return status.New(codes.Internal, err.Error())
+}
+
+func withDetails(st *status.Status, info *errdetails.ErrorInfo, vs []errorsx.Violation) *status.Status {
+	details := []protoadapt.MessageV1{info}
+	if len(vs) > 0 {
+		br := &errdetails.BadRequest{}
+		for i, v := range vs {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Reason:      v.Code,
+				Description: v.Message,
+			})
+			if v.Params != nil && len(v.Params) == 0 {
+				info.Metadata[metaParam+strconv.Itoa(i)] = ""
+			}
+			for name, val := range v.Params {
+				info.Metadata[fmt.Sprintf("%s%d.%s", metaParam, i, name)] = val
+			}
+		}
+		details = append(details, br)
+	}
+	if withD, err := st.WithDetails(details...); err == nil {
+		return withD
+	}
+	return st
+}
+
+// validationInfo — ErrorInfo для *errorsx.ValidationError (и InvalidArgumentError).
+func validationInfo() *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason:   "VALIDATION_FAILED",
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{metaKind: string(errorsx.KindInvalid), metaValidation: "true"},
+	}
+}
+
+func codeForKind(k errorsx.Kind) codes.Code {
+	switch k {
+	case errorsx.KindInvalid:
+		return codes.InvalidArgument
+	case errorsx.KindNotFound:
+		return codes.NotFound
+	case errorsx.KindAlreadyExists:
+		return codes.AlreadyExists
+	case errorsx.KindConflict, errorsx.KindAborted:
+		return codes.Aborted
+	case errorsx.KindFailedPrecondition:
+		return codes.FailedPrecondition
+	case errorsx.KindUnauthenticated:
+		return codes.Unauthenticated
+	case errorsx.KindPermission:
+		return codes.PermissionDenied
+	case errorsx.KindRateLimited:
+		return codes.ResourceExhausted
+	case errorsx.KindUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// ===== status -> errorsx =====
+
+// FromStatus — обратное к ToStatus. По ErrorInfo домена ErrorDomain: *errorsx.E,
+// *errorsx.ValidationError (в том числе без нарушений) или обёртка сентинела с тем же Kind.
+// Без него: BadRequest — *errorsx.ValidationError, иначе обёртка корневого сентинела по коду
+// (errors.Is(err, errorsx.ErrNotFound) и т.п.). CANCELED — context.Canceled. OK или nil — nil.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	var (
+		info *errdetails.ErrorInfo
+		br   *errdetails.BadRequest
+	)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == ErrorDomain {
+				info = d
+			}
+		case *errdetails.BadRequest:
+			br = d
+		}
+	}
+	vs := violationsOf(br, info)
+	meta := info.GetMetadata()
+
+	switch {
+	case info != nil && meta[metaValidation] == "true":
+		ve := errorsx.NewValidation()
+		for _, v := range vs {
+			ve.Add(v.Field, v.Code, v.Message, v.Params)
+		}
+		return ve
+	case info != nil && meta[metaSentinel] == "true":
+		root := sentinelFor(errorsx.Kind(meta[metaKind]))
+		if msg, ok := causeOf(st.Message(), root); ok {
+			return fmt.Errorf("%w: %s", root, msg)
+		}
+		return root
+	case info != nil:
+		kind := errorsx.Kind(meta[metaKind])
+		retryable := meta[metaRetryable] == "true"
+		var cause error
+		if msg, ok := causeOf(st.Message(), sentinelFor(kind)); ok {
+			cause = errors.New(msg)
+		}
+		return errorsx.Wrap(kind, info.GetReason(), retryable, vs, cause)
+	case len(vs) > 0:
+		// BadRequest без нашего ErrorInfo (чужой сервер) — тоже нарушения по полям.
+		ve := errorsx.NewValidation()
+		for _, v := range vs {
+			ve.Add(v.Field, v.Code, v.Message, v.Params)
+		}
+		return ve
+	}
+
+	// Статус без ErrorInfo домена ErrorDomain: класс — только по коду.
+	switch st.Code() {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.Unknown:
+		return fmt.Errorf("%w: %s", errorsx.ErrInternal, st.Message())
+	}
+	root := sentinelForCode(st.Code())
+	if msg, ok := causeOf(st.Message(), root); ok {
+		return fmt.Errorf("%w: %s", root, msg)
+	}
+	return root
+}
+
+// FromError — FromStatus для ошибки вызова. Ошибки без статуса (локальные, уже errorsx) —
+// как есть.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := errorsx.AsE(err); ok {
+		return err
+	}
+	if _, ok := errorsx.AsValidation(err); ok {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st)
+}
+
+// violationsOf — нарушения из BadRequest (reason = Code, description = Message) и их Params
+// из ErrorInfo.metadata (см. withDetails).
+func violationsOf(br *errdetails.BadRequest, info *errdetails.ErrorInfo) []errorsx.Violation {
+	if br == nil || len(br.GetFieldViolations()) == 0 {
+		return nil
+	}
+	vs := make([]errorsx.Violation, 0, len(br.GetFieldViolations()))
+	for _, fv := range br.GetFieldViolations() {
+		vs = append(vs, errorsx.Violation{Field: fv.GetField(), Code: fv.GetReason(), Message: fv.GetDescription()})
+	}
+	for key, val := range info.GetMetadata() {
+		rest, ok := strings.CutPrefix(key, metaParam)
+		if !ok {
+			continue
+		}
+		idx, name, named := strings.Cut(rest, ".")
+		i, err := strconv.Atoi(idx)
+		if err != nil || i < 0 || i >= len(vs) {
+			continue
+		}
+		if vs[i].Params == nil {
+			vs[i].Params = make(map[string]string)
+		}
+		if named {
+			vs[i].Params[name] = val
+		}
+	}
+	return vs
+}
+
+// causeOf — текст причины из сообщения "<сентинел>: <причина>".
+func causeOf(msg string, root error) (string, bool) {
+	if root == nil || msg == root.Error() {
+		return "", false
+	}
+	if rest, ok := strings.CutPrefix(msg, root.Error()+": "); ok {
+		return rest, true
+	}
+	return msg, msg != ""
+}
+
+func sentinelFor(k errorsx.Kind) error {
+	switch k {
+	case errorsx.KindInvalid:
+		return errorsx.ErrInvalidArgument
+	case errorsx.KindNotFound:
+		return errorsx.ErrNotFound
+	case errorsx.KindAlreadyExists:
+		return errorsx.ErrAlreadyExists
+	case errorsx.KindConflict:
+		return errorsx.ErrConflict
+	case errorsx.KindAborted:
+		return errorsx.ErrAborted
+	case errorsx.KindFailedPrecondition:
+		return errorsx.ErrFailedPrecondition
+	case errorsx.KindUnauthenticated:
+		return errorsx.ErrUnauthenticated
+	case errorsx.KindPermission:
+		return errorsx.ErrPermissionDenied
+	case errorsx.KindRateLimited:
+		return errorsx.ErrResourceExhausted
+	case errorsx.KindUnavailable:
+		return errorsx.ErrUnavailable
+	default:
+		return errorsx.ErrInternal
+	}
+}
+
+// sentinelForCode — корневой сентинел по коду статуса без ErrorInfo. ABORTED — всегда ErrAborted:
+// CONFLICT свои ошибки несут в ErrorInfo (metaKind).
+func sentinelForCode(c codes.Code) error {
+	if c == codes.DeadlineExceeded {
+		return errorsx.ErrUnavailable // таймаут на стороне сервера/сети — временная недоступность
+	}
+	if root := classOfCode(c); root != nil {
+		return root
+	}
+	return errorsx.ErrInternal
+}
+
+// ===== Интерсепторы =====
+
+// ErrorsUnaryServerInterceptor — ошибка хендлера уходит клиенту как ToStatus(err):
+// хендлеры могут возвращать доменные ошибки errorsx как есть.
+func ErrorsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+func ErrorsStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// ErrorsUnaryClientInterceptor — ошибка вызова приходит как FromError(err); status.Code(err)
+// и детали статуса при этом продолжают работать (для ретраев/автоматов дальше по цепочке).
+func ErrorsUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return clientError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+func ErrorsStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, clientError(err)
+		}
+		return errorsClientStream{cs}, nil
+	}
+}
+
+type errorsClientStream struct {
+	grpc.ClientStream
+}
+
+func (s errorsClientStream) RecvMsg(m any) error { return clientError(s.ClientStream.RecvMsg(m)) }
+func (s errorsClientStream) SendMsg(m any) error { return clientError(s.ClientStream.SendMsg(m)) }
+
+// clientError — FromError, сохраняющий исходный статус (io.EOF и локальные ошибки — как есть).
+func clientError(err error) error {
+	st, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+	if _, isE := errorsx.AsE(err); isE {
+		return err
+	}
+	return &statusError{err: FromStatus(st), st: st}
+}
+
+// statusError — восстановленная ошибка errorsx + исходный статус.
+type statusError struct {
+	err error
+	st  *status.Status
+}
+
+func (e *statusError) Error() string              { return e.err.Error() }
+func (e *statusError) Unwrap() error              { return e.err }
+func (e *statusError) GRPCStatus() *status.Status { return e.st }
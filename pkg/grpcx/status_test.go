@@ -0,0 +1,165 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
+var allKinds = []errorsx.Kind{
+	errorsx.KindInvalid,
+	errorsx.KindNotFound,
+	errorsx.KindAlreadyExists,
+	errorsx.KindConflict,
+	errorsx.KindAborted,
+	errorsx.KindFailedPrecondition,
+	errorsx.KindUnauthenticated,
+	errorsx.KindPermission,
+	errorsx.KindRateLimited,
+	errorsx.KindUnavailable,
+	errorsx.KindInternal,
+}
+
+// overWire — ToStatus, сериализация как на проводе, FromStatus.
+func overWire(t *testing.T, err error) (error, codes.Code) {
+	t.Helper()
+	b, merr := proto.Marshal(ToStatus(err).Proto())
+	if merr != nil {
+		t.Fatalf("marshal status: %v", merr)
+	}
+	var p spb.Status
+	if uerr := proto.Unmarshal(b, &p); uerr != nil {
+		t.Fatalf("unmarshal status: %v", uerr)
+	}
+	st := status.FromProto(&p)
+	return FromStatus(st), st.Code()
+}
+
+func TestStatusRoundTripE(t *testing.T) {
+	vs := []errorsx.Violation{
+		{Field: "qty", Code: "OUT_OF_RANGE", Message: "qty must be > 0", Params: map[string]string{"min": "1", "max": "100"}},
+		{Field: "sku", Code: "REQUIRED", Message: "sku is required"},
+		{Field: "note", Code: "EMPTY_PARAMS", Params: map[string]string{}},
+	}
+	for _, k := range allKinds {
+		for _, tc := range []struct {
+			name string
+			err  error
+		}{
+			{"bare", errorsx.Wrap(k, "", false, nil, nil)},
+			{"code+cause", errorsx.Wrap(k, "SOME_CODE", true, nil, errors.New("item 42: boom"))},
+			{"violations", errorsx.Wrap(k, "WITH_VIOLATIONS", false, vs, errors.New("bad"))},
+		} {
+			t.Run(string(k)+"/"+tc.name, func(t *testing.T) {
+				got, code := overWire(t, tc.err)
+				if want := codeForKind(k); code != want {
+					t.Fatalf("code = %v, want %v", code, want)
+				}
+				e, ok := errorsx.AsE(got)
+				if !ok {
+					t.Fatalf("FromStatus = %T %v, want *errorsx.E", got, got)
+				}
+				want, _ := errorsx.AsE(tc.err)
+				if !reflect.DeepEqual(e, want) {
+					t.Fatalf("round trip:\n got  %#v\n want %#v", e, want)
+				}
+				if got.Error() != tc.err.Error() || errors.Unwrap(e).Error() != want.Err.Error() {
+					t.Fatalf("message = %q / %q, want %q / %q", got, errors.Unwrap(e), tc.err, want.Err)
+				}
+			})
+		}
+	}
+}
+
+func TestStatusRoundTripSentinel(t *testing.T) {
+	wrappers := map[errorsx.Kind]func(string) error{
+		errorsx.KindInvalid:            errorsx.InvalidArgument,
+		errorsx.KindNotFound:           errorsx.NotFound,
+		errorsx.KindAlreadyExists:      errorsx.AlreadyExists,
+		errorsx.KindConflict:           errorsx.Conflict,
+		errorsx.KindAborted:            errorsx.Aborted,
+		errorsx.KindFailedPrecondition: errorsx.FailedPrecondition,
+		errorsx.KindUnauthenticated:    errorsx.Unauthenticated,
+		errorsx.KindPermission:         errorsx.PermissionDenied,
+		errorsx.KindRateLimited:        errorsx.ResourceExhausted,
+		errorsx.KindUnavailable:        errorsx.Unavailable,
+		errorsx.KindInternal:           errorsx.Internal,
+	}
+	for _, k := range allKinds {
+		t.Run(string(k), func(t *testing.T) {
+			err := wrappers[k]("item 7: version 3")
+			got, _ := overWire(t, err)
+			if _, isE := errorsx.AsE(got); isE {
+				t.Fatalf("FromStatus = *errorsx.E, want a sentinel wrap")
+			}
+			if errorsx.KindOf(got) != k {
+				t.Fatalf("KindOf = %q, want %q", errorsx.KindOf(got), k)
+			}
+			if got.Error() != err.Error() {
+				t.Fatalf("message = %q, want %q", got, err)
+			}
+		})
+	}
+}
+
+func TestStatusRoundTripValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ve   *errorsx.ValidationError
+	}{
+		{"empty", errorsx.NewValidation()},
+		{"violations", errorsx.NewValidation().
+			Add("name", "TOO_LONG", "name is too long", map[string]string{"max": "64"}).
+			Add("code", "REQUIRED", "code is required", nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, code := overWire(t, tc.ve)
+			if code != codes.InvalidArgument {
+				t.Fatalf("code = %v, want InvalidArgument", code)
+			}
+			ve, ok := errorsx.AsValidation(got)
+			if !ok {
+				t.Fatalf("FromStatus = %T %v, want *errorsx.ValidationError", got, got)
+			}
+			if !reflect.DeepEqual(ve, tc.ve) {
+				t.Fatalf("round trip:\n got  %#v\n want %#v", ve.Violations(), tc.ve.Violations())
+			}
+		})
+	}
+}
+
+func TestInvalidArgumentErrorDecodesAsValidation(t *testing.T) {
+	vs := []errorsx.Violation{{Field: "qty", Code: "OUT_OF_RANGE", Message: "too big", Params: map[string]string{"max": "10"}}}
+	ve, ok := errorsx.AsValidation(FromError(InvalidArgumentError("", vs)))
+	if !ok || !reflect.DeepEqual(ve.Violations(), vs) {
+		t.Fatalf("FromError(InvalidArgumentError) = %v, want violations %v", ve, vs)
+	}
+}
+
+func TestStatusWithoutErrorInfo(t *testing.T) {
+	for _, tc := range []struct {
+		st   *status.Status
+		want error
+	}{
+		{status.New(codes.NotFound, "nope"), errorsx.ErrNotFound},
+		{status.New(codes.Aborted, "conflict: looks like one"), errorsx.ErrAborted},
+		{status.New(codes.DeadlineExceeded, "slow"), errorsx.ErrUnavailable},
+		{status.New(codes.Unknown, "?"), errorsx.ErrInternal},
+		{status.New(codes.Canceled, "gone"), context.Canceled},
+	} {
+		if got := FromStatus(tc.st); !errors.Is(got, tc.want) {
+			t.Errorf("FromStatus(%v) = %v, want errors.Is %v", tc.st.Code(), got, tc.want)
+		}
+	}
+	if err := FromStatus(status.New(codes.OK, "")); err != nil {
+		t.Errorf("FromStatus(OK) = %v, want nil", err)
+	}
+}
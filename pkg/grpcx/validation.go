@@ -0,0 +1,67 @@
+package grpcx
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InvalidArgumentError строит gRPC-ошибку с кодом INVALID_ARGUMENT
+// и прикручивает google.rpc.BadRequest с FieldViolation'ами (reason — Code нарушения,
+// description — Message) и ErrorInfo, как ToStatus для *errorsx.ValidationError:
+// grpcx.FromStatus на клиенте вернёт *errorsx.ValidationError с теми же нарушениями.
+// summary — короткий заголовок ("invalid request"); пустой заменяется на дефолт.
+func InvalidArgumentError(summary string, v []errorsx.Violation) error {
+	if summary == "" {
+		summary = "invalid request"
+	}
+	st := status.New(codes.InvalidArgument, summary)
+	return withDetails(st, validationInfo(), sortedViolations(v)).Err()
+}
+
+// IsInvalidArgument — быстро проверить код gRPC-ошибки.
+func IsInvalidArgument(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.InvalidArgument
+}
+
+// ExtractBadRequest — достать google.rpc.BadRequest из gRPC-ошибки (если есть).
+func ExtractBadRequest(err error) (*errdetails.BadRequest, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			return br, true
+		}
+	}
+	return nil, false
+}
+
+// ---- внутреннее ----
+
+// sortedViolations — копия в стабильном порядке (для тестов/логов); пустое поле — "_".
+func sortedViolations(v []errorsx.Violation) []errorsx.Violation {
+	vs := slices.Clone(v)
+	sort.SliceStable(vs, func(i, j int) bool {
+		a, b := vs[i], vs[j]
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Message < b.Message
+	})
+	for i := range vs {
+		if vs[i].Field == "" {
+			vs[i].Field = "_" // защита от пустых имён
+		}
+	}
+	return vs
+}
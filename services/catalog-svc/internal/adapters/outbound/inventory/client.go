@@ -1,150 +1,59 @@
 package inventory
 
 import (
-	"context"
-	"errors"
 	"time"
 
-	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-)
 
-// ===== Доменные ошибки-заглушки (заменишь на свои из domain/app) =====
-var (
-	ErrNotFound     = errors.New("not found")
-	ErrInvalidInput = errors.New("invalid input")
-	ErrUnavailable  = errors.New("inventory unavailable")
-	ErrDeadline     = errors.New("inventory deadline exceeded")
-	ErrInternal     = errors.New("inventory internal error")
+	invclient "github.com/YanMak/ecommerce/v2/clients/inventory"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
 )
 
-// ===== DTO-заглушки (заменишь на свои доменные) =====
-type Stock struct {
-	ItemID    int64
-	Available int64
-	// Locations можно добавить при надобности
-	UpdatedAt time.Time
-}
+// Outbound-адаптер catalog → inventory-svc. Сам клиент — общий SDK (clients/inventory),
+// здесь только его сборка под нужды catalog.
+//
+// Ошибки — классы pkg/errorsx: errorsx.IsNotFound(err), errorsx.IsUnavailable(err) и т.п.
 
-// ===== Клиент =====
-type Client struct {
-	cli     invpb.StockServiceClient
-	timeout time.Duration
-	retries int
-}
+type (
+	Client        = invclient.Client
+	Stock         = invclient.Stock
+	StockLocation = invclient.StockLocation
+)
 
-// NewFromConn — простой конструктор.
-func NewFromConn(conn *grpc.ClientConn, timeout time.Duration, retries int) *Client {
-	if timeout <= 0 {
-		timeout = 2 * time.Second
-	}
-	if retries < 0 {
-		retries = 0
-	}
-	return &Client{
-		cli:     invpb.NewStockServiceClient(conn),
-		timeout: timeout,
-		retries: retries,
-	}
-}
+// Config — сборка клиента для catalog. Нулевое значение — как было до SDK: одна попытка
+// с таймаутом 2s, без склейки и без hedging.
+type Config struct {
+	Timeout time.Duration // на одну попытку; 0 — 2s
+	Retries int           // повторы временных ошибок; 0 — без повторов
 
-// New — если хочешь передать уже собранный StockServiceClient (например, обёрнутый интерсепторами).
-func New(cli invpb.StockServiceClient, timeout time.Duration, retries int) *Client {
-	if timeout <= 0 {
-		timeout = 2 * time.Second
-	}
-	if retries < 0 {
-		retries = 0
-	}
-	return &Client{cli: cli, timeout: timeout, retries: retries}
-}
+	// BatchWindow > 0 — GetStock по товарам страницы склеиваются в BatchGetStock
+	// (не больше BatchMax id, 0 — предел SDK).
+	BatchWindow time.Duration
+	BatchMax    int
 
-// GetStock — чтение одного товара (опц. по локации).
-func (c *Client) GetStock(ctx context.Context, itemID int64, locationCode string) (Stock, error) {
-	req := &invpb.GetStockRequest{ItemId: itemID, LocationCode: locationCode}
-	var lastErr error
-	for attempt := 0; attempt <= c.retries; attempt++ {
-		ctxT, cancel := context.WithTimeout(ctx, c.timeout)
-		resp, err := c.cli.GetStock(ctxT, req)
-		cancel()
-		if err == nil {
-			return toDTO(resp.GetStock()), nil
-		}
-		// Маппим коды и решаем — ретраить или нет
-		st, _ := status.FromError(err)
-		switch st.Code() {
-		case codes.InvalidArgument:
-			return Stock{}, ErrInvalidInput
-		case codes.NotFound:
-			return Stock{}, ErrNotFound
-		case codes.DeadlineExceeded:
-			lastErr = ErrDeadline
-		case codes.Unavailable:
-			lastErr = ErrUnavailable
-		default:
-			lastErr = ErrInternal
-		}
-		// Ретраим только на сетевые/временные
-		if st.Code() == codes.Unavailable || st.Code() == codes.DeadlineExceeded {
-			time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
-			continue
-		}
-		return Stock{}, lastErr
-	}
-	return Stock{}, lastErr
+	// Hedging — медленный хвост GetStock/BatchGetStock страхуется копией запроса
+	// (grpcx.DefaultHedgePolicy: не больше 10% вызовов).
+	Hedging bool
 }
 
-// BatchGetStock — батч для листингов.
-func (c *Client) BatchGetStock(ctx context.Context, itemIDs []int64, locationCode string) ([]Stock, error) {
-	req := &invpb.BatchGetStockRequest{ItemIds: itemIDs, LocationCode: locationCode}
-	var lastErr error
-	for attempt := 0; attempt <= c.retries; attempt++ {
-		ctxT, cancel := context.WithTimeout(ctx, c.timeout)
-		resp, err := c.cli.BatchGetStock(ctxT, req)
-		cancel()
-		if err == nil {
-			out := make([]Stock, 0, len(resp.GetStocks()))
-			for _, pb := range resp.GetStocks() {
-				out = append(out, toDTO(pb))
-			}
-			return out, nil
-		}
-		st, _ := status.FromError(err)
-		switch st.Code() {
-		case codes.InvalidArgument:
-			return nil, ErrInvalidInput
-		case codes.NotFound:
-			return nil, ErrNotFound
-		case codes.DeadlineExceeded:
-			lastErr = ErrDeadline
-		case codes.Unavailable:
-			lastErr = ErrUnavailable
-		default:
-			lastErr = ErrInternal
-		}
-		if st.Code() == codes.Unavailable || st.Code() == codes.DeadlineExceeded {
-			time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
-			continue
-		}
-		return nil, lastErr
-	}
-	return nil, lastErr
+// NewFromConn — клиент поверх общего соединения catalog-svc.
+// timeout — на одну попытку, retries — повторы временных ошибок (0 — одна попытка).
+func NewFromConn(conn grpc.ClientConnInterface, timeout time.Duration, retries int) *Client {
+	return NewFromConfig(conn, Config{Timeout: timeout, Retries: retries})
 }
 
-// ===== Вспомогательное =====
-func toDTO(pb *invpb.Stock) Stock {
-	if pb == nil {
-		return Stock{}
+// NewFromConfig — то же с явной конфигурацией: склейка и hedging включаются только здесь.
+func NewFromConfig(conn grpc.ClientConnInterface, cfg Config) *Client {
+	opts := []invclient.Option{
+		invclient.WithTimeout(cfg.Timeout),
+		invclient.WithRetries(max(cfg.Retries, 0)),
+		invclient.WithActor("catalog-svc"),
 	}
-	t := time.Time{}
-	if ts := pb.GetUpdatedAt(); ts != nil {
-		t = time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC()
+	if cfg.BatchWindow > 0 {
+		opts = append(opts, invclient.WithBatching(cfg.BatchWindow, cfg.BatchMax))
 	}
-	return Stock{
-		ItemID:    pb.GetItemId(),
-		Available: pb.GetAvailable(),
-		UpdatedAt: t,
+	if cfg.Hedging {
+		opts = append(opts, invclient.WithHedging(grpcx.DefaultHedgePolicy()))
 	}
+	return invclient.New(conn, opts...)
 }
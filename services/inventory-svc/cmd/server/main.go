@@ -2,47 +2,32 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
 	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+	"github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/outbound/filestore"
+	"github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/app"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
-// ---- Временная заглушка под порт приложения ----
-// Заменишь на свою реализацию InventoryQueries из слоя app.
-type dummyQueries struct{}
-
-func (d *dummyQueries) GetStock(ctx context.Context, itemID int64, locationCode string) (grpcstock.StockDTO, error) {
-	return grpcstock.StockDTO{
-		ItemID:    itemID,
-		Available: 0,
-		Locations: nil,
-		UpdatedAt: time.Now(),
-	}, nil
-}
-
-func (d *dummyQueries) BatchGetStock(ctx context.Context, itemIDs []int64, locationCode string) ([]grpcstock.StockDTO, error) {
-	out := make([]grpcstock.StockDTO, 0, len(itemIDs))
-	now := time.Now()
-	for _, id := range itemIDs {
-		out = append(out, grpcstock.StockDTO{
-			ItemID:    id,
-			Available: 0,
-			Locations: nil,
-			UpdatedAt: now,
-		})
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
 	}
-	return out, nil
 }
 
-func main() {
+// run — весь сервер; ошибка возвращается, а не log.Fatal, чтобы отработали defer (store.Close).
+func run() error {
 	// Ctrl+C / SIGTERM -> корректная остановка
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -50,18 +35,134 @@ func main() {
 	addr := ":8081"
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Fatalf("listen %s: %v", addr, err)
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+	defer lis.Close()
+
+	// Одно хранилище обслуживает и чтение, и admin-команды.
+	// INVENTORY_DATA_DIR задан — состояние переживает рестарт (журнал + снапшоты), иначе чисто in-memory.
+	var inv *app.Inventory
+	if dir := os.Getenv("INVENTORY_DATA_DIR"); dir != "" {
+		store, err := openStore(dir)
+		if err != nil {
+			return fmt.Errorf("open store: %w", err)
+		}
+		defer store.Close()
+
+		inv = app.NewInventory(app.WithLedger(store))
+		snap, entries, err := store.Load()
+		if err != nil {
+			return fmt.Errorf("load store: %w", err)
+		}
+		if err := inv.Restore(snap, entries); err != nil {
+			return fmt.Errorf("restore inventory: %w", err)
+		}
+		log.Printf("inventory restored from %s (snapshot seq=%d, wal entries=%d)", dir, snap.Seq, len(entries))
+		go store.Run(ctx, inv.Snapshot)
+	} else {
+		inv = app.NewInventory()
 	}
 
-	grpcSrv := grpc.NewServer()
-	// ВАЖНО: используем правильный конструктор и реальную (или заглушечную) реализацию порта
-	invpb.RegisterStockServiceServer(grpcSrv, grpcstock.NewServer(&dummyQueries{}))
+	// Admin-RPC идемпотентны по metadata "idempotency-key" (см. stock_admin.proto).
+	idemTTL := 24 * time.Hour
+	if v := os.Getenv("INVENTORY_IDEMPOTENCY_TTL"); v != "" {
+		if idemTTL, err = time.ParseDuration(v); err != nil {
+			return fmt.Errorf("INVENTORY_IDEMPOTENCY_TTL: %w", err)
+		}
+	}
+	idem := grpcx.IdempotencyUnaryServerInterceptor(grpcx.NewMemoryIdempotencyStore(), idemTTL,
+		invpb.StockAdminService_AdjustStock_FullMethodName,
+		invpb.StockAdminService_SetStock_FullMethodName,
+		invpb.StockAdminService_BatchAdjustStock_FullMethodName,
+		invpb.StockAdminService_TransferStock_FullMethodName,
+	)
+
+	// Предел батч-запросов; клиенты узнают его через StockService.GetLimits (int32).
+	maxBatch := grpcstock.DefaultMaxBatch
+	if v := os.Getenv("INVENTORY_MAX_BATCH"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("INVENTORY_MAX_BATCH: must be a positive 32-bit integer, got %q", v)
+		}
+		maxBatch = int(n)
+	}
+	srvOpts := []grpcstock.Option{grpcstock.WithMaxBatch(maxBatch)}
+
+	// Просроченные резервы освобождают остаток фоном.
+	go inv.RunReservationExpiry(ctx, time.Second)
+
+	// Размеры сообщений, keepalive и возраст соединений; переопределяются INVENTORY_GRPC_* (см. grpcx.LimitsFromEnv).
+	limits, err := grpcx.LimitsFromEnv("INVENTORY_GRPC_", grpcx.DefaultLimits())
+	if err != nil {
+		return fmt.Errorf("grpc limits: %w", err)
+	}
+	grpcOpts, err := limits.ServerOptions()
+	if err != nil {
+		return fmt.Errorf("grpc limits: %w", err)
+	}
+
+	// Стандартная цепочка: request-id, access-лог, ошибки errorsx -> статус с ErrorInfo/BadRequest,
+	// паники -> INTERNAL. Идемпотентность — внутри неё.
+	grpcOpts = append(grpcOpts, grpcx.ServerChain(grpcx.ServerChainConfig{
+		Unary: []grpc.UnaryServerInterceptor{idem},
+	})...)
+	grpcSrv := grpc.NewServer(grpcOpts...)
+	invpb.RegisterStockServiceServer(grpcSrv, grpcstock.NewServer(inv, inv, inv, srvOpts...))
+	invpb.RegisterStockAdminServiceServer(grpcSrv, grpcstock.NewAdminServer(inv, inv, inv, srvOpts...))
+	invpb.RegisterLocationAdminServiceServer(grpcSrv, grpcstock.NewLocationServer(inv))
 
 	// Удобно для grpcurl / отладки
 	reflection.Register(grpcSrv)
 
-	<-ctx.Done()
+	served := make(chan error, 1)
+	go func() {
+		log.Printf("inventory-svc listening on %s", addr)
+		served <- grpcSrv.Serve(lis)
+	}()
+
+	select {
+	case err := <-served:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
 	log.Println("shutting down gracefully...")
-	grpcSrv.GracefulStop()
-	_ = lis.Close()
+	// WatchStock-стримы сами не завершаются: даём unary-запросам доработать и рвём остальное.
+	stopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		grpcSrv.Stop()
+	}
+	return nil
+}
+
+// openStore — файловое хранилище; политика fsync и частота снапшотов из ENV:
+// INVENTORY_FSYNC=always|interval|never, INVENTORY_SNAPSHOT_EVERY=<записей>, INVENTORY_SNAPSHOT_INTERVAL=<duration>,
+// INVENTORY_SNAPSHOT_MOVEMENTS=<движений в снапшоте, -1 — все>.
+func openStore(dir string) (*filestore.Store, error) {
+	policy, err := filestore.ParseSyncPolicy(os.Getenv("INVENTORY_FSYNC"))
+	if err != nil {
+		return nil, err
+	}
+	opts := filestore.Options{Sync: policy, SnapshotEvery: 1000, SnapshotInterval: 5 * time.Minute}
+	if v := os.Getenv("INVENTORY_SNAPSHOT_EVERY"); v != "" {
+		if opts.SnapshotEvery, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("INVENTORY_SNAPSHOT_EVERY: %w", err)
+		}
+	}
+	if v := os.Getenv("INVENTORY_SNAPSHOT_INTERVAL"); v != "" {
+		if opts.SnapshotInterval, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("INVENTORY_SNAPSHOT_INTERVAL: %w", err)
+		}
+	}
+	if v := os.Getenv("INVENTORY_SNAPSHOT_MOVEMENTS"); v != "" {
+		if opts.SnapshotMovements, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("INVENTORY_SNAPSHOT_MOVEMENTS: %w", err)
+		}
+	}
+	return filestore.Open(dir, opts)
 }
@@ -1,14 +1,252 @@
 package grpcstock
 
+import (
+	"time"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+)
+
 type StockDTO struct {
 	ItemID    int64
-	Available int64
+	Available int64 // OnHand - Reserved
+	Reserved  int64
+	OnHand    int64
+	InTransit int64 // едет между локациями, в Available/OnHand не входит
 	Locations []StockPerLocationDTO
-	UpdatedAt int64 // unix seconds
+	UpdatedAt time.Time
 }
 
 type StockPerLocationDTO struct {
 	LocationCode string
-	Available    int64
-	UpdatedAt    int64 // unix seconds
+	Available    int64 // OnHand - Reserved
+	Reserved     int64
+	OnHand       int64
+	InTransit    int64     // едет В эту локацию, ещё не принято
+	UpdatedAt    time.Time // полная точность (наносекунды) — сравнивается в prev_updated_at
+	Version      int64     // растёт на 1 при каждом изменении локации
+}
+
+// ChangeReason — причина изменения остатка (зеркало invpb.StockChangeReason).
+type ChangeReason int32
+
+const (
+	ReasonUnspecified ChangeReason = iota
+	ReasonReceipt
+	ReasonCorrection
+	ReasonReturn
+	ReasonManual
+	ReasonSale
+	ReasonTransfer
+)
+
+// AdjustCommand — инкремент/декремент остатка по одной локации.
+type AdjustCommand struct {
+	ItemID        int64
+	LocationCode  string
+	Delta         int64
+	Reason        ChangeReason
+	Reference     string
+	AllowNegative bool
+	Actor         string // кто меняет (metadata "x-actor"), попадает в журнал движений
+	Precondition
+}
+
+// AdjustLineResult — итог строки батча в режиме best-effort: либо Stock, либо Err.
+type AdjustLineResult struct {
+	Stock StockDTO
+	Err   error
+}
+
+// SetCommand — установка точного значения остатка по одной локации.
+type SetCommand struct {
+	ItemID       int64
+	LocationCode string
+	NewAvailable int64
+	Reason       ChangeReason
+	Reference    string
+	Actor        string
+	Precondition
+}
+
+// Precondition — оптимистическая блокировка по локации. Нулевые значения — без проверки;
+// если заданы оба поля, должны совпасть оба.
+type Precondition struct {
+	PrevUpdatedAt   time.Time
+	ExpectedVersion int64
+}
+
+// VersionConflictError — оптимистическая блокировка не прошла.
+// Current — актуальное состояние товара, адаптер отдаёт его клиенту в details ABORTED.
+type VersionConflictError struct {
+	Current      StockDTO
+	LocationCode string
+	Err          error // содержит errorsx.ErrAborted
+}
+
+func NewVersionConflict(current StockDTO, location, reason string) *VersionConflictError {
+	return &VersionConflictError{
+		Current:      current,
+		LocationCode: location,
+		Err:          errorsx.Abortedf("item %d at %q: %s", current.ItemID, location, reason),
+	}
+}
+
+func (e *VersionConflictError) Error() string { return e.Err.Error() }
+func (e *VersionConflictError) Unwrap() error { return e.Err }
+
+// MovementDTO — запись журнала движений: одно изменение остатка по одной локации.
+type MovementDTO struct {
+	ID           int64
+	ItemID       int64
+	LocationCode string
+	Delta        int64
+	Before       int64
+	After        int64
+	Reason       ChangeReason
+	Reference    string
+	Actor        string
+	CreatedAt    time.Time
+}
+
+// MovementFilter — фильтр и страница для ListMovements. Нулевые поля — без фильтра.
+type MovementFilter struct {
+	ItemID       int64
+	LocationCode string
+	Reasons      []ChangeReason
+	Reference    string
+	Since        time.Time // включительно
+	Until        time.Time // не включительно
+
+	BeforeID int64 // курсор: только записи с ID < BeforeID; 0 — с самых новых
+	Limit    int
+}
+
+// MovementPage — страница журнала (от новых к старым).
+type MovementPage struct {
+	Movements []MovementDTO
+	HasMore   bool
+}
+
+// ReservationState — состояние резерва (зеркало invpb.ReservationState).
+type ReservationState int32
+
+const (
+	ReservationUnspecified ReservationState = iota
+	ReservationActive
+	ReservationCommitted
+	ReservationReleased
+	ReservationExpired
+)
+
+// ReserveCommand — удержать Quantity единиц на локации на время TTL.
+type ReserveCommand struct {
+	ItemID       int64
+	LocationCode string
+	Quantity     int64
+	TTL          time.Duration
+	Reference    string
+}
+
+type ReservationDTO struct {
+	ID           string
+	ItemID       int64
+	LocationCode string
+	Quantity     int64
+	State        ReservationState
+	Reference    string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// TransferState — состояние перемещения (зеркало invpb.TransferState).
+type TransferState int32
+
+const (
+	TransferUnspecified TransferState = iota
+	TransferInTransit
+	TransferReceived
+	TransferCancelled
+)
+
+// TransferCommand — переместить Quantity единиц из From в To.
+// InTransit=false — сразу на получателе; true — до ReceiveTransfer товар числится в пути.
+type TransferCommand struct {
+	ItemID              int64
+	From                string
+	To                  string
+	Quantity            int64
+	Reference           string
+	InTransit           bool
+	Actor               string
+	ExpectedFromVersion int64 // 0 — без проверки
+}
+
+type TransferDTO struct {
+	ID        string
+	ItemID    int64
+	From      string
+	To        string
+	Quantity  int64
+	State     TransferState
+	Reference string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WatchCursor — позиция в потоке изменений: эпоха экземпляра хранилища + номер записи журнала.
+// Эпоха меняется при рестарте процесса, поэтому курсор «чужого» экземпляра не примут за свой.
+type WatchCursor struct {
+	Epoch string
+	Seq   uint64
+}
+
+// StockChanges — изменившиеся товары и курсор, с которого продолжать.
+type StockChanges struct {
+	Stocks []StockDTO
+	Cursor WatchCursor
+}
+
+// LocationType — вид локации (зеркало invpb.LocationType).
+type LocationType int32
+
+const (
+	LocationTypeUnspecified LocationType = iota
+	LocationWarehouse
+	LocationStore
+	LocationPickupPoint
+)
+
+// LocationStatus — статус локации (зеркало invpb.LocationStatus).
+type LocationStatus int32
+
+const (
+	LocationStatusUnspecified LocationStatus = iota
+	LocationActive
+	LocationInactive
+)
+
+type LocationDTO struct {
+	Code      string
+	Name      string
+	City      string
+	Type      LocationType
+	Status    LocationStatus
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// LocationFilter — пустые поля не фильтруют.
+type LocationFilter struct {
+	Status LocationStatus
+	Type   LocationType
+	City   string
+}
+
+// LocationPatch — частичное обновление: nil-поля не меняются.
+type LocationPatch struct {
+	Code   string
+	Name   *string
+	City   *string
+	Type   *LocationType
+	Status *LocationStatus
 }
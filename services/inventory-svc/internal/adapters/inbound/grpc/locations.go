@@ -0,0 +1,162 @@
+package grpcstock
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+)
+
+// ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
+
+// LocationRegistry — входной порт реестра локаций. Формат кода и длины полей проверяет
+// use case и возвращает *errorsx.ValidationError; адаптер отдаёт его как BadRequest.
+type LocationRegistry interface {
+	CreateLocation(ctx context.Context, loc LocationDTO) (LocationDTO, error)
+	GetLocation(ctx context.Context, code string) (LocationDTO, error)
+	ListLocations(ctx context.Context, f LocationFilter) ([]LocationDTO, error)
+	UpdateLocation(ctx context.Context, p LocationPatch) (LocationDTO, error)
+	// DeleteLocation — только для пустой локации (без остатка и резервов).
+	DeleteLocation(ctx context.Context, code string) error
+}
+
+// ===== gRPC-СЕРВЕР =====
+
+type LocationServer struct {
+	invpb.UnimplementedLocationAdminServiceServer
+	l LocationRegistry
+}
+
+func NewLocationServer(l LocationRegistry) *LocationServer {
+	return &LocationServer{l: l}
+}
+
+func (s *LocationServer) CreateLocation(ctx context.Context, req *invpb.CreateLocationRequest) (*invpb.CreateLocationResponse, error) {
+	in := req.GetLocation()
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "location is required")
+	}
+	typ, err := locationTypeFromPB(in.GetType())
+	if err != nil {
+		return nil, err
+	}
+	st, err := locationStatusFromPB(in.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	if st == LocationStatusUnspecified {
+		st = LocationActive
+	}
+	loc, err := s.l.CreateLocation(ctx, LocationDTO{
+		Code:   in.GetCode(),
+		Name:   in.GetName(),
+		City:   in.GetCity(),
+		Type:   typ,
+		Status: st,
+	})
+	if err != nil {
+		return nil, commandStatus(err, "create location")
+	}
+	return &invpb.CreateLocationResponse{Location: toPBLocationInfo(loc)}, nil
+}
+
+func (s *LocationServer) GetLocation(ctx context.Context, req *invpb.GetLocationRequest) (*invpb.GetLocationResponse, error) {
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	loc, err := s.l.GetLocation(ctx, req.GetCode())
+	if err != nil {
+		return nil, commandStatus(err, "get location")
+	}
+	return &invpb.GetLocationResponse{Location: toPBLocationInfo(loc)}, nil
+}
+
+func (s *LocationServer) ListLocations(ctx context.Context, req *invpb.ListLocationsRequest) (*invpb.ListLocationsResponse, error) {
+	typ, err := locationTypeFromPB(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+	st, err := locationStatusFromPB(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	locs, err := s.l.ListLocations(ctx, LocationFilter{Status: st, Type: typ, City: req.GetCity()})
+	if err != nil {
+		return nil, commandStatus(err, "list locations")
+	}
+	resp := &invpb.ListLocationsResponse{Locations: make([]*invpb.Location, 0, len(locs))}
+	for _, l := range locs {
+		resp.Locations = append(resp.Locations, toPBLocationInfo(l))
+	}
+	return resp, nil
+}
+
+func (s *LocationServer) UpdateLocation(ctx context.Context, req *invpb.UpdateLocationRequest) (*invpb.UpdateLocationResponse, error) {
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	p := LocationPatch{Code: req.GetCode(), Name: req.Name, City: req.City}
+	if req.Type != nil {
+		typ, err := locationTypeFromPB(req.GetType())
+		if err != nil {
+			return nil, err
+		}
+		p.Type = &typ
+	}
+	if req.Status != nil {
+		st, err := locationStatusFromPB(req.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		if st == LocationStatusUnspecified {
+			return nil, status.Error(codes.InvalidArgument, "status must be ACTIVE or INACTIVE")
+		}
+		p.Status = &st
+	}
+	loc, err := s.l.UpdateLocation(ctx, p)
+	if err != nil {
+		return nil, commandStatus(err, "update location")
+	}
+	return &invpb.UpdateLocationResponse{Location: toPBLocationInfo(loc)}, nil
+}
+
+func (s *LocationServer) DeleteLocation(ctx context.Context, req *invpb.DeleteLocationRequest) (*invpb.DeleteLocationResponse, error) {
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	if err := s.l.DeleteLocation(ctx, req.GetCode()); err != nil {
+		return nil, commandStatus(err, "delete location")
+	}
+	return &invpb.DeleteLocationResponse{}, nil
+}
+
+// ===== МАППИНГ =====
+
+func locationTypeFromPB(t invpb.LocationType) (LocationType, error) {
+	if _, ok := invpb.LocationType_name[int32(t)]; !ok {
+		return LocationTypeUnspecified, status.Errorf(codes.InvalidArgument, "unknown location type: %d", t)
+	}
+	return LocationType(t), nil
+}
+
+func locationStatusFromPB(s invpb.LocationStatus) (LocationStatus, error) {
+	if _, ok := invpb.LocationStatus_name[int32(s)]; !ok {
+		return LocationStatusUnspecified, status.Errorf(codes.InvalidArgument, "unknown location status: %d", s)
+	}
+	return LocationStatus(s), nil
+}
+
+// toPBLocationInfo — запись реестра (не путать с toPBLocation — остатком по локации).
+func toPBLocationInfo(l LocationDTO) *invpb.Location {
+	return &invpb.Location{
+		Code:      l.Code,
+		Name:      l.Name,
+		City:      l.City,
+		Type:      invpb.LocationType(l.Type),
+		Status:    invpb.LocationStatus(l.Status),
+		CreatedAt: toProtoTs(l.CreatedAt),
+		UpdatedAt: toProtoTs(l.UpdatedAt),
+	}
+}
@@ -0,0 +1,99 @@
+package grpcstock
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+)
+
+// ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
+
+// ReservationCommands — входной порт резервов: держим остаток под checkout, не списывая его.
+// Вместе с резервом возвращается актуальный Stock товара.
+type ReservationCommands interface {
+	Reserve(ctx context.Context, cmd ReserveCommand) (ReservationDTO, StockDTO, error)
+	// Commit списывает зарезервированное с on_hand. Повторный Commit — не ошибка.
+	Commit(ctx context.Context, reservationID string) (ReservationDTO, StockDTO, error)
+	// Release отпускает резерв. Повторный Release (и Release истёкшего) — не ошибка.
+	Release(ctx context.Context, reservationID string) (ReservationDTO, StockDTO, error)
+}
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 24 * time.Hour
+)
+
+// ===== gRPC-ХЕНДЛЕРЫ (StockService) =====
+
+func (s *Server) ReserveStock(ctx context.Context, req *invpb.ReserveStockRequest) (*invpb.ReserveStockResponse, error) {
+	if req.GetItemId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "item_id must be > 0")
+	}
+	if req.GetLocationCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "location_code is required")
+	}
+	if req.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be > 0")
+	}
+	ttl := defaultReservationTTL
+	if d := req.GetTtl(); d != nil {
+		if err := d.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ttl: %v", err)
+		}
+		ttl = d.AsDuration()
+		if ttl <= 0 || ttl > maxReservationTTL {
+			return nil, status.Errorf(codes.InvalidArgument, "ttl must be in (0, %s]", maxReservationTTL)
+		}
+	}
+
+	res, st, err := s.r.Reserve(ctx, ReserveCommand{
+		ItemID:       req.GetItemId(),
+		LocationCode: req.GetLocationCode(),
+		Quantity:     req.GetQuantity(),
+		TTL:          ttl,
+		Reference:    req.GetReference(),
+	})
+	if err != nil {
+		return nil, commandStatus(err, "reserve stock")
+	}
+	return &invpb.ReserveStockResponse{Reservation: toPBReservation(res), Stock: toPBStock(st, "")}, nil
+}
+
+func (s *Server) CommitReservation(ctx context.Context, req *invpb.CommitReservationRequest) (*invpb.CommitReservationResponse, error) {
+	if req.GetReservationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reservation_id is required")
+	}
+	res, st, err := s.r.Commit(ctx, req.GetReservationId())
+	if err != nil {
+		return nil, commandStatus(err, "commit reservation")
+	}
+	return &invpb.CommitReservationResponse{Reservation: toPBReservation(res), Stock: toPBStock(st, "")}, nil
+}
+
+func (s *Server) ReleaseReservation(ctx context.Context, req *invpb.ReleaseReservationRequest) (*invpb.ReleaseReservationResponse, error) {
+	if req.GetReservationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reservation_id is required")
+	}
+	res, st, err := s.r.Release(ctx, req.GetReservationId())
+	if err != nil {
+		return nil, commandStatus(err, "release reservation")
+	}
+	return &invpb.ReleaseReservationResponse{Reservation: toPBReservation(res), Stock: toPBStock(st, "")}, nil
+}
+
+func toPBReservation(r ReservationDTO) *invpb.Reservation {
+	return &invpb.Reservation{
+		ReservationId: r.ID,
+		ItemId:        r.ItemID,
+		LocationCode:  r.LocationCode,
+		Quantity:      r.Quantity,
+		State:         invpb.ReservationState(r.State),
+		Reference:     r.Reference,
+		CreatedAt:     toProtoTs(r.CreatedAt),
+		ExpiresAt:     toProtoTs(r.ExpiresAt),
+	}
+}
@@ -0,0 +1,415 @@
+package grpcstock
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
+
+// InventoryCommands — входной (application) порт для изменения остатков.
+// Доменные ошибки возвращаются как классы errorsx (ErrNotFound, ErrAborted и т.п.),
+// адаптер сам переводит их в gRPC-коды.
+type InventoryCommands interface {
+	AdjustStock(ctx context.Context, cmd AdjustCommand) (StockDTO, error)
+	SetStock(ctx context.Context, cmd SetCommand) (StockDTO, error)
+
+	// Батч применяется атомарно: либо все строки, либо ни одной.
+	// Возвращает stocks в порядке строк запроса.
+	BatchAdjustStock(ctx context.Context, lines []AdjustCommand) ([]StockDTO, error)
+	// BatchAdjustStockPartial — best-effort: применяет прошедшие проверки строки, упавшие
+	// пропускает. Результаты — в порядке строк; error — только сбой батча целиком.
+	BatchAdjustStockPartial(ctx context.Context, lines []AdjustCommand) ([]AdjustLineResult, error)
+}
+
+// MovementQueries — входной порт чтения журнала движений.
+type MovementQueries interface {
+	// ListMovements возвращает записи от новых к старым, не больше f.Limit.
+	ListMovements(ctx context.Context, f MovementFilter) (MovementPage, error)
+}
+
+// ===== gRPC-СЕРВЕР =====
+
+const (
+	defaultMovementsPage = 100
+	maxMovementsPage     = 1000
+)
+
+type AdminServer struct {
+	invpb.UnimplementedStockAdminServiceServer
+	c InventoryCommands
+	m MovementQueries
+	t TransferCommands
+	limits
+}
+
+func NewAdminServer(c InventoryCommands, m MovementQueries, t TransferCommands, opts ...Option) *AdminServer {
+	return &AdminServer{c: c, m: m, t: t, limits: limitsOf(opts)}
+}
+
+func (s *AdminServer) AdjustStock(ctx context.Context, req *invpb.AdjustStockRequest) (*invpb.AdjustStockResponse, error) {
+	cmd, err := adjustFromPB(req.GetItemId(), req.GetLocationCode(), req.GetDelta(), req.GetReason(),
+		req.GetReference(), req.GetAllowNegative(), req.GetPrevUpdatedAt(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+	cmd.Actor = grpcx.ActorFromContext(ctx)
+
+	st, err := s.c.AdjustStock(ctx, cmd)
+	if err != nil {
+		return nil, commandStatus(err, "adjust stock")
+	}
+	return &invpb.AdjustStockResponse{Stock: toPBStock(st, "")}, nil
+}
+
+func (s *AdminServer) SetStock(ctx context.Context, req *invpb.SetStockRequest) (*invpb.SetStockResponse, error) {
+	if req.GetItemId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "item_id must be > 0")
+	}
+	if req.GetLocationCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "location_code is required")
+	}
+	if req.GetNewAvailable() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "new_available must be >= 0")
+	}
+	reason, err := reasonFromPB(req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	pre, err := preconditionFromPB(req.GetPrevUpdatedAt(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.c.SetStock(ctx, SetCommand{
+		ItemID:       req.GetItemId(),
+		LocationCode: req.GetLocationCode(),
+		NewAvailable: req.GetNewAvailable(),
+		Reason:       reason,
+		Reference:    req.GetReference(),
+		Actor:        grpcx.ActorFromContext(ctx),
+		Precondition: pre,
+	})
+	if err != nil {
+		return nil, commandStatus(err, "set stock")
+	}
+	return &invpb.SetStockResponse{Stock: toPBStock(st, "")}, nil
+}
+
+func (s *AdminServer) BatchAdjustStock(ctx context.Context, req *invpb.BatchAdjustStockRequest) (*invpb.BatchAdjustStockResponse, error) {
+	lines := req.GetLines()
+	if l := len(lines); l == 0 {
+		return nil, status.Error(codes.InvalidArgument, "lines is empty")
+	} else if l > s.maxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "too many lines: %d > %d", l, s.maxBatch)
+	}
+	mode := req.GetMode()
+	if _, ok := invpb.BatchMode_name[int32(mode)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown mode: %d", mode)
+	}
+	if mode == invpb.BatchMode_BATCH_MODE_BEST_EFFORT {
+		return s.batchAdjustBestEffort(ctx, lines)
+	}
+
+	actor := grpcx.ActorFromContext(ctx)
+	cmds := make([]AdjustCommand, 0, len(lines))
+	for i, ln := range lines {
+		cmd, err := adjustLineFromPB(ln)
+		if err != nil {
+			st, _ := status.FromError(err)
+			return nil, status.Errorf(st.Code(), "lines[%d]: %s", i, st.Message())
+		}
+		cmd.Actor = actor
+		cmds = append(cmds, cmd)
+	}
+
+	stocks, err := s.c.BatchAdjustStock(ctx, cmds)
+	if err != nil {
+		return nil, commandStatus(err, "batch adjust stock")
+	}
+	if len(stocks) != len(cmds) {
+		return nil, internalf("batch adjust stock: app returned %d stocks for %d lines", len(stocks), len(cmds))
+	}
+
+	out := make([]*invpb.Stock, 0, len(stocks))
+	for _, st := range stocks {
+		out = append(out, toPBStock(st, ""))
+	}
+	return &invpb.BatchAdjustStockResponse{Stocks: out}, nil
+}
+
+// batchAdjustBestEffort — строки с битым вводом отсекаем здесь же, остальные отдаём use case'у;
+// итог собираем по исходным индексам.
+func (s *AdminServer) batchAdjustBestEffort(ctx context.Context, lines []*invpb.BatchAdjustLine) (*invpb.BatchAdjustStockResponse, error) {
+	actor := grpcx.ActorFromContext(ctx)
+	resp := &invpb.BatchAdjustStockResponse{Results: make([]*invpb.BatchAdjustLineResult, len(lines))}
+	cmds := make([]AdjustCommand, 0, len(lines))
+	idx := make([]int, 0, len(lines)) // cmds[j] — строка idx[j] запроса
+	for i, ln := range lines {
+		resp.Results[i] = &invpb.BatchAdjustLineResult{Index: int32(i)}
+		cmd, err := adjustLineFromPB(ln)
+		if err != nil {
+			resp.Results[i].Error = lineError(err)
+			continue
+		}
+		cmd.Actor = actor
+		cmds = append(cmds, cmd)
+		idx = append(idx, i)
+	}
+
+	if len(cmds) > 0 {
+		results, err := s.c.BatchAdjustStockPartial(ctx, cmds)
+		if err != nil {
+			return nil, commandStatus(err, "batch adjust stock")
+		}
+		if len(results) != len(cmds) {
+			return nil, internalf("batch adjust stock: app returned %d results for %d lines", len(results), len(cmds))
+		}
+		for j, r := range results {
+			if r.Err != nil {
+				resp.Results[idx[j]].Error = lineError(r.Err)
+				continue
+			}
+			resp.Results[idx[j]].Stock = toPBStock(r.Stock, "")
+		}
+	}
+
+	for _, r := range resp.Results {
+		if r.Error != nil {
+			resp.Failed++
+		} else {
+			resp.Applied++
+		}
+	}
+	return resp, nil
+}
+
+func (s *AdminServer) ListStockMovements(ctx context.Context, req *invpb.ListStockMovementsRequest) (*invpb.ListStockMovementsResponse, error) {
+	if req.GetItemId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "item_id must be >= 0")
+	}
+	size := int(req.GetPageSize())
+	switch {
+	case size < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must be >= 0")
+	case size == 0:
+		size = defaultMovementsPage
+	case size > maxMovementsPage:
+		size = maxMovementsPage
+	}
+	beforeID, err := decodeMovementToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	f := MovementFilter{
+		ItemID:       req.GetItemId(),
+		LocationCode: req.GetLocationCode(),
+		Reference:    req.GetReference(),
+		BeforeID:     beforeID,
+		Limit:        size,
+	}
+	for _, r := range req.GetReasons() {
+		cr, err := reasonFromPB(r)
+		if err != nil {
+			return nil, err
+		}
+		f.Reasons = append(f.Reasons, cr)
+	}
+	if f.Since, err = validTs("since", req.GetSince()); err != nil {
+		return nil, err
+	}
+	if f.Until, err = validTs("until", req.GetUntil()); err != nil {
+		return nil, err
+	}
+
+	page, err := s.m.ListMovements(ctx, f)
+	if err != nil {
+		return nil, commandStatus(err, "list stock movements")
+	}
+
+	resp := &invpb.ListStockMovementsResponse{Movements: make([]*invpb.StockMovement, 0, len(page.Movements))}
+	for _, m := range page.Movements {
+		resp.Movements = append(resp.Movements, toPBMovement(m))
+	}
+	if page.HasMore && len(page.Movements) > 0 {
+		resp.NextPageToken = encodeMovementToken(page.Movements[len(page.Movements)-1].ID)
+	}
+	return resp, nil
+}
+
+// ===== ВАЛИДАЦИЯ И МАППИНГ =====
+
+func adjustLineFromPB(ln *invpb.BatchAdjustLine) (AdjustCommand, error) {
+	return adjustFromPB(ln.GetItemId(), ln.GetLocationCode(), ln.GetDelta(), ln.GetReason(),
+		ln.GetReference(), ln.GetAllowNegative(), ln.GetPrevUpdatedAt(), ln.GetExpectedVersion())
+}
+
+func adjustFromPB(itemID int64, location string, delta int64, reason invpb.StockChangeReason,
+	reference string, allowNegative bool, prevUpdatedAt *timestamppb.Timestamp, expectedVersion int64) (AdjustCommand, error) {
+	if itemID <= 0 {
+		return AdjustCommand{}, status.Error(codes.InvalidArgument, "item_id must be > 0")
+	}
+	if location == "" {
+		return AdjustCommand{}, status.Error(codes.InvalidArgument, "location_code is required")
+	}
+	if delta == 0 {
+		return AdjustCommand{}, status.Error(codes.InvalidArgument, "delta must be != 0")
+	}
+	r, err := reasonFromPB(reason)
+	if err != nil {
+		return AdjustCommand{}, err
+	}
+	pre, err := preconditionFromPB(prevUpdatedAt, expectedVersion)
+	if err != nil {
+		return AdjustCommand{}, err
+	}
+	return AdjustCommand{
+		ItemID:        itemID,
+		LocationCode:  location,
+		Delta:         delta,
+		Reason:        r,
+		Reference:     reference,
+		AllowNegative: allowNegative,
+		Precondition:  pre,
+	}, nil
+}
+
+func reasonFromPB(r invpb.StockChangeReason) (ChangeReason, error) {
+	if _, ok := invpb.StockChangeReason_name[int32(r)]; !ok {
+		return ReasonUnspecified, status.Errorf(codes.InvalidArgument, "unknown reason: %d", r)
+	}
+	return ChangeReason(r), nil
+}
+
+func preconditionFromPB(prevUpdatedAt *timestamppb.Timestamp, expectedVersion int64) (Precondition, error) {
+	prev, err := validTs("prev_updated_at", prevUpdatedAt)
+	if err != nil {
+		return Precondition{}, err
+	}
+	if expectedVersion < 0 {
+		return Precondition{}, status.Error(codes.InvalidArgument, "expected_version must be >= 0")
+	}
+	return Precondition{PrevUpdatedAt: prev, ExpectedVersion: expectedVersion}, nil
+}
+
+// validTs — опциональный Timestamp из запроса; nil -> нулевое время, битый -> INVALID_ARGUMENT.
+func validTs(field string, ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "%s: %v", field, err)
+	}
+	return ts.AsTime(), nil
+}
+
+func toPBMovement(m MovementDTO) *invpb.StockMovement {
+	return &invpb.StockMovement{
+		Id:           m.ID,
+		ItemId:       m.ItemID,
+		LocationCode: m.LocationCode,
+		Delta:        m.Delta,
+		Before:       m.Before,
+		After:        m.After,
+		Reason:       invpb.StockChangeReason(m.Reason),
+		Reference:    m.Reference,
+		Actor:        m.Actor,
+		CreatedAt:    toProtoTs(m.CreatedAt),
+	}
+}
+
+// page_token — непрозрачный для клиента курсор: base64 от ID последней отданной записи.
+func encodeMovementToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+func decodeMovementToken(tok string) (int64, error) {
+	if tok == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("bad cursor")
+	}
+	return id, nil
+}
+
+// codeVersionConflict — reason ErrorInfo (и LineError.code) для несовпавшей версии: по нему
+// клиент отличает конфликт версий от прочих ABORTED.
+const codeVersionConflict = "VERSION_CONFLICT"
+
+// commandStatus — перевод доменной ошибки в gRPC-статус по конвенции stock_admin.proto.
+func commandStatus(err error, op string) error {
+	// Конфликт версий: кладём актуальный Stock в details, чтобы клиент мог перечитать и повторить.
+	var vc *VersionConflictError
+	if errors.As(err, &vc) {
+		st := grpcx.ToStatus(errorsx.AbortedWithCause(codeVersionConflict, err))
+		if withCur, derr := st.WithDetails(toPBStock(vc.Current, "")); derr == nil {
+			st = withCur
+		}
+		return st.Err()
+	}
+
+	// Классы errorsx — общим маппингом grpcx: код по классу, Code/Retryable — в ErrorInfo,
+	// нарушения по полям — в google.rpc.BadRequest (клиент подсветит конкретное поле).
+	if _, isE := errorsx.AsE(err); isE {
+		return grpcx.ToStatus(err).Err()
+	}
+	if k := errorsx.KindOf(err); k != "" && k != errorsx.KindInternal {
+		return grpcx.ToStatus(err).Err()
+	}
+	return internalf("%s failed: %v", op, err)
+}
+
+// lineError — ошибка строки батча в том же виде, что commandStatus отдал бы для всего RPC:
+// code — reason из ErrorInfo (errorsx.CodeOf), а без него — Kind ошибки; нарушения — с параметрами.
+// Принимает и доменные ошибки, и gRPC-статусы валидации из adjustFromPB.
+func lineError(err error) *invpb.LineError {
+	var vc *VersionConflictError
+	if errors.As(err, &vc) {
+		return &invpb.LineError{
+			Status:  int32(codes.Aborted),
+			Code:    codeVersionConflict,
+			Message: err.Error(),
+			Current: toPBStock(vc.Current, ""),
+		}
+	}
+
+	st := grpcx.ToStatus(err)
+	if _, ok := status.FromError(err); ok {
+		err = grpcx.FromStatus(st) // статус с ErrorInfo/BadRequest -> исходная ошибка errorsx
+	}
+	le := &invpb.LineError{Status: int32(st.Code()), Code: errorsx.CodeOf(err), Message: st.Message()}
+	var vs []errorsx.Violation
+	if ve, ok := errorsx.AsValidation(err); ok {
+		le.Code, vs = "VALIDATION_FAILED", ve.Violations()
+	} else if e, ok := errorsx.AsE(err); ok {
+		vs = e.Violations
+	}
+	if le.Code == "" {
+		le.Code = string(errorsx.KindOf(err))
+	}
+	if le.Code == "" {
+		le.Code = code.Code(st.Code()).String()
+	}
+	for _, v := range vs {
+		le.Violations = append(le.Violations, &invpb.FieldViolation{Field: v.Field, Code: v.Code, Message: v.Message, Params: v.Params})
+	}
+	return le
+}
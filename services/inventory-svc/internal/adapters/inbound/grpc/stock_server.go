@@ -2,8 +2,10 @@ package grpcstock
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -11,6 +13,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
 )
 
 // ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
@@ -21,28 +24,64 @@ type InventoryQueries interface {
 	// Иначе — можно вернуть все локации, а адаптер отфильтрует сам.
 	GetStock(ctx context.Context, itemID int64, locationCode string) (StockDTO, error)
 
-	// Возвращает stocks в произвольном порядке (адаптер сам упорядочит под запрос).
+	// Возвращает stocks найденных товаров в произвольном порядке (адаптер сам упорядочит
+	// под запрос). Отсутствующие просто не попадают в ответ — это не ошибка.
 	BatchGetStock(ctx context.Context, itemIDs []int64, locationCode string) ([]StockDTO, error)
+
+	// BatchGetStockAsOf — остатки на момент asOf по журналу движений (только on_hand).
+	// Товары без единой локации на тот момент в ответ не попадают.
+	BatchGetStockAsOf(ctx context.Context, itemIDs []int64, asOf time.Time) ([]StockDTO, error)
 }
 
-// Сентинелы/чекеры доменных ошибок (примерные)
+// Сентинелы/чекеры доменных ошибок — общие с pkg/errorsx.
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound = errorsx.ErrNotFound
 )
 
-func isNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
-
 // ===== gRPC-СЕРВЕР =====
 
-const maxBatch = 500
+// DefaultMaxBatch — предел item_ids/lines в одном запросе, если не задан WithMaxBatch.
+const DefaultMaxBatch = 500
+
+// Option — настройка gRPC-серверов (общая для Server и AdminServer).
+type Option func(*limits)
+
+type limits struct {
+	maxBatch int
+}
+
+// WithMaxBatch — предел item_ids в BatchGetStock/WatchStock и lines в BatchAdjustStock.
+// Клиенты узнают его через GetLimits (int32), поэтому больше math.MaxInt32 не бывает.
+func WithMaxBatch(n int) Option {
+	return func(l *limits) {
+		if n > 0 {
+			l.maxBatch = min(n, math.MaxInt32)
+		}
+	}
+}
+
+func limitsOf(opts []Option) limits {
+	l := limits{maxBatch: DefaultMaxBatch}
+	for _, o := range opts {
+		o(&l)
+	}
+	return l
+}
 
 type Server struct {
 	invpb.UnimplementedStockServiceServer
 	q InventoryQueries
+	r ReservationCommands
+	w StockWatcher
+	limits
+}
+
+func NewServer(q InventoryQueries, r ReservationCommands, w StockWatcher, opts ...Option) *Server {
+	return &Server{q: q, r: r, w: w, limits: limitsOf(opts)}
 }
 
-func NewServer(q InventoryQueries) *Server {
-	return &Server{q: q}
+func (s *Server) GetLimits(ctx context.Context, req *invpb.GetLimitsRequest) (*invpb.GetLimitsResponse, error) {
+	return &invpb.GetLimitsResponse{MaxBatchSize: int32(s.maxBatch)}, nil
 }
 
 func (s *Server) GetStock(ctx context.Context, req *invpb.GetStockRequest) (*invpb.GetStockResponse, error) {
@@ -52,13 +91,19 @@ func (s *Server) GetStock(ctx context.Context, req *invpb.GetStockRequest) (*inv
 		return nil, status.Error(codes.InvalidArgument, "item_id must be > 0")
 	}
 	location := req.GetLocationCode() // может быть пустой
+	asOf, err := validTs("as_of", req.GetAsOf())
+	if err != nil {
+		return nil, err
+	}
 
-	st, err := s.q.GetStock(ctx, itemID, location)
+	var st StockDTO
+	if asOf.IsZero() {
+		st, err = s.q.GetStock(ctx, itemID, location)
+	} else {
+		st, err = s.stockAsOf(ctx, itemID, asOf)
+	}
 	if err != nil {
-		if isNotFound(err) {
-			return nil, status.Error(codes.NotFound, "item not found")
-		}
-		return nil, status.Errorf(codes.Internal, "get stock failed: %v", err)
+		return nil, commandStatus(err, "get stock")
 	}
 
 	pb := toPBStock(st, location)
@@ -69,8 +114,8 @@ func (s *Server) BatchGetStock(ctx context.Context, req *invpb.BatchGetStockRequ
 	ids := req.GetItemIds()
 	if l := len(ids); l == 0 {
 		return nil, status.Error(codes.InvalidArgument, "item_ids is empty")
-	} else if l > maxBatch {
-		return nil, status.Errorf(codes.InvalidArgument, "too many item_ids: %d > %d", l, maxBatch)
+	} else if l > s.maxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "too many item_ids: %d > %d", l, s.maxBatch)
 	}
 	location := req.GetLocationCode()
 
@@ -87,13 +132,23 @@ func (s *Server) BatchGetStock(ctx context.Context, req *invpb.BatchGetStockRequ
 		}
 	}
 
-	stocks, err := s.q.BatchGetStock(ctx, unique, location)
+	mode := req.GetMode()
+	if _, ok := invpb.BatchGetMode_name[int32(mode)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown mode: %d", mode)
+	}
+	asOf, err := validTs("as_of", req.GetAsOf())
 	if err != nil {
-		if isNotFound(err) {
-			// Конвенция: если хотя бы один из запрошенных отсутствует — NOT_FOUND.
-			return nil, status.Error(codes.NotFound, "some item_id not found")
-		}
-		return nil, status.Errorf(codes.Internal, "batch get stock failed: %v", err)
+		return nil, err
+	}
+
+	var stocks []StockDTO
+	if asOf.IsZero() {
+		stocks, err = s.q.BatchGetStock(ctx, unique, location)
+	} else {
+		stocks, err = s.q.BatchGetStockAsOf(ctx, unique, asOf)
+	}
+	if err != nil {
+		return nil, commandStatus(err, "batch get stock")
 	}
 
 	// Сложим в map для быстрого доступа.
@@ -102,18 +157,52 @@ func (s *Server) BatchGetStock(ctx context.Context, req *invpb.BatchGetStockRequ
 		byID[st.ItemID] = st
 	}
 
+	var missing []int64
+	for _, id := range unique {
+		if _, ok := byID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 && mode != invpb.BatchGetMode_BATCH_GET_MODE_PARTIAL {
+		// Конвенция строгого режима: если хотя бы один из запрошенных отсутствует — NOT_FOUND.
+		return nil, status.Errorf(codes.NotFound, "item_id not found: %s", joinIDs(missing))
+	}
+
 	// Сформируем ответ в ТОЧНОМ порядке входных ids (с учётом повторов).
 	out := make([]*invpb.Stock, 0, len(ids))
 	for _, id := range ids {
-		st, ok := byID[id]
-		if !ok {
-			// Если usecase вернул не для всех — считаем это логической ошибкой уровня app.
-			return nil, status.Errorf(codes.Internal, "missing stock for item_id=%d in app response", id)
+		if st, ok := byID[id]; ok {
+			out = append(out, toPBStock(st, location))
 		}
-		out = append(out, toPBStock(st, location))
 	}
 
-	return &invpb.BatchGetStockResponse{Stocks: out}, nil
+	return &invpb.BatchGetStockResponse{Stocks: out, MissingItemIds: missing}, nil
+}
+
+// stockAsOf — одиночный товар на момент asOf; «не было» — ErrNotFound, как у GetStock.
+func (s *Server) stockAsOf(ctx context.Context, itemID int64, asOf time.Time) (StockDTO, error) {
+	stocks, err := s.q.BatchGetStockAsOf(ctx, []int64{itemID}, asOf)
+	if err != nil {
+		return StockDTO{}, err
+	}
+	if len(stocks) == 0 {
+		return StockDTO{}, errorsx.NotFoundf("item %d as of %s", itemID, asOf.UTC().Format(time.RFC3339Nano))
+	}
+	return stocks[0], nil
+}
+
+// joinIDs — "3, 7, 12" для сообщений об ошибках; длинные списки обрезаем.
+func joinIDs(ids []int64) string {
+	const limit = 10
+	parts := make([]string, 0, min(len(ids), limit)+1)
+	for i, id := range ids {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("… (+%d)", len(ids)-limit))
+			break
+		}
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // ===== МАППИНГ В PROTO =====
@@ -122,7 +211,10 @@ func toPBStock(s StockDTO, filterLocation string) *invpb.Stock {
 	pb := &invpb.Stock{
 		ItemId:    s.ItemID,
 		Available: s.Available,
-		UpdatedAt: toProtoTsSec(s.UpdatedAt),
+		Reserved:  s.Reserved,
+		OnHand:    s.OnHand,
+		InTransit: s.InTransit,
+		UpdatedAt: toProtoTs(s.UpdatedAt),
 	}
 
 	// Если попросили конкретную локацию — отфильтруем и пересчитаем available.
@@ -137,10 +229,13 @@ func toPBStock(s StockDTO, filterLocation string) *invpb.Stock {
 		if only != nil {
 			pb.Locations = []*invpb.StockPerLocation{toPBLocation(*only)}
 			pb.Available = only.Available
+			pb.Reserved = only.Reserved
+			pb.OnHand = only.OnHand
+			pb.InTransit = only.InTransit
 		} else {
 			// Нет такой локации у товара — считаем available=0 и пустой список.
 			pb.Locations = nil
-			pb.Available = 0
+			pb.Available, pb.Reserved, pb.OnHand, pb.InTransit = 0, 0, 0, 0
 		}
 		return pb
 	}
@@ -157,31 +252,23 @@ func toPBLocation(l StockPerLocationDTO) *invpb.StockPerLocation {
 	return &invpb.StockPerLocation{
 		LocationCode: l.LocationCode,
 		Available:    l.Available,
-		UpdatedAt:    toProtoTsSec(l.UpdatedAt),
+		Reserved:     l.Reserved,
+		OnHand:       l.OnHand,
+		InTransit:    l.InTransit,
+		UpdatedAt:    toProtoTs(l.UpdatedAt),
+		Version:      l.Version,
 	}
 }
 
-// новый хелпер: из unix seconds -> protobuf Timestamp
-func toProtoTsSec(sec int64) *timestamppb.Timestamp {
-	if sec <= 0 {
-		return nil // нет значения — оставим пусто
-	}
-	return timestamppb.New(time.Unix(sec, 0).UTC())
-}
-
+// toProtoTs — time.Time -> protobuf Timestamp без потери наносекунд.
 func toProtoTs(t time.Time) *timestamppb.Timestamp {
-	// invpb.Timestamp — это алиас google.protobuf.Timestamp из сгенерённого пакета.
-	// Обычно генератор кладёт её в well-known types; в реальном коде используем timestamppb.New(t).
 	if t.IsZero() {
-		return nil
+		return nil // нет значения — оставим пусто
 	}
-	// Здесь просто покажем безопасный конверт; в реальном проекте импортируй timestamppb.
-	sec := t.Unix()
-	nsec := int32(t.Sub(time.Unix(sec, 0)))
-	return &timestamppb.Timestamp{Seconds: sec, Nanos: int32(nsec)}
+	return timestamppb.New(t)
 }
 
 // (опционально) удобный враппер для внутренних ошибок
 func internalf(format string, a ...any) error {
-	return status.Errorf(codes.Internal, fmt.Sprintf(format, a...))
+	return status.Error(codes.Internal, fmt.Sprintf(format, a...))
 }
@@ -0,0 +1,95 @@
+package grpcstock
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+)
+
+// ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
+
+// TransferCommands — входной порт перемещений между локациями. Обе стороны меняются
+// одной записью журнала, поэтому «списали, но не оприходовали» невозможно.
+// Вместе с перемещением возвращается актуальный Stock товара.
+type TransferCommands interface {
+	Transfer(ctx context.Context, cmd TransferCommand) (TransferDTO, StockDTO, error)
+	// ReceiveTransfer оприходует товар в пути на получателе. Повторный вызов — не ошибка.
+	ReceiveTransfer(ctx context.Context, transferID, actor string) (TransferDTO, StockDTO, error)
+	// CancelTransfer возвращает товар в пути на источник. Повторный вызов — не ошибка.
+	CancelTransfer(ctx context.Context, transferID, actor string) (TransferDTO, StockDTO, error)
+}
+
+// ===== gRPC-ХЕНДЛЕРЫ (StockAdminService) =====
+
+func (s *AdminServer) TransferStock(ctx context.Context, req *invpb.TransferStockRequest) (*invpb.TransferStockResponse, error) {
+	if req.GetItemId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "item_id must be > 0")
+	}
+	if req.GetFromLocationCode() == "" || req.GetToLocationCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_location_code and to_location_code are required")
+	}
+	if req.GetFromLocationCode() == req.GetToLocationCode() {
+		return nil, status.Error(codes.InvalidArgument, "from_location_code and to_location_code must differ")
+	}
+	if req.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be > 0")
+	}
+	if req.GetExpectedFromVersion() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expected_from_version must be >= 0")
+	}
+
+	tr, st, err := s.t.Transfer(ctx, TransferCommand{
+		ItemID:              req.GetItemId(),
+		From:                req.GetFromLocationCode(),
+		To:                  req.GetToLocationCode(),
+		Quantity:            req.GetQuantity(),
+		Reference:           req.GetReference(),
+		InTransit:           req.GetInTransit(),
+		Actor:               grpcx.ActorFromContext(ctx),
+		ExpectedFromVersion: req.GetExpectedFromVersion(),
+	})
+	if err != nil {
+		return nil, commandStatus(err, "transfer stock")
+	}
+	return &invpb.TransferStockResponse{Transfer: toPBTransfer(tr), Stock: toPBStock(st, "")}, nil
+}
+
+func (s *AdminServer) ReceiveTransfer(ctx context.Context, req *invpb.ReceiveTransferRequest) (*invpb.ReceiveTransferResponse, error) {
+	if req.GetTransferId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "transfer_id is required")
+	}
+	tr, st, err := s.t.ReceiveTransfer(ctx, req.GetTransferId(), grpcx.ActorFromContext(ctx))
+	if err != nil {
+		return nil, commandStatus(err, "receive transfer")
+	}
+	return &invpb.ReceiveTransferResponse{Transfer: toPBTransfer(tr), Stock: toPBStock(st, "")}, nil
+}
+
+func (s *AdminServer) CancelTransfer(ctx context.Context, req *invpb.CancelTransferRequest) (*invpb.CancelTransferResponse, error) {
+	if req.GetTransferId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "transfer_id is required")
+	}
+	tr, st, err := s.t.CancelTransfer(ctx, req.GetTransferId(), grpcx.ActorFromContext(ctx))
+	if err != nil {
+		return nil, commandStatus(err, "cancel transfer")
+	}
+	return &invpb.CancelTransferResponse{Transfer: toPBTransfer(tr), Stock: toPBStock(st, "")}, nil
+}
+
+func toPBTransfer(t TransferDTO) *invpb.Transfer {
+	return &invpb.Transfer{
+		TransferId:       t.ID,
+		ItemId:           t.ItemID,
+		FromLocationCode: t.From,
+		ToLocationCode:   t.To,
+		Quantity:         t.Quantity,
+		State:            invpb.TransferState(t.State),
+		Reference:        t.Reference,
+		CreatedAt:        toProtoTs(t.CreatedAt),
+		UpdatedAt:        toProtoTs(t.UpdatedAt),
+	}
+}
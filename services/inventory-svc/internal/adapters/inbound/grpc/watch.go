@@ -0,0 +1,119 @@
+package grpcstock
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	invpb "github.com/YanMak/ecommerce/v2/gen/inventory/v1"
+)
+
+// ===== ПОРТ ПРИЛОЖЕНИЯ (use case интерфейс) =====
+
+// StockWatcher — входной порт подписки на изменения остатков.
+// Модель «по состоянию»: наружу отдаём актуальный Stock, а не дельты, поэтому
+// повторная доставка безопасна, а пропуск лечится перечитыванием.
+type StockWatcher interface {
+	// Subscribe — сигнал после каждой записи в журнал. Канал не закрывается; cancel — отписка.
+	Subscribe() (notify <-chan struct{}, cancel func())
+	// StockChanges — текущее состояние тех itemIDs, что изменились после since
+	// (с учётом locationCode). Если since «чужой» или слишком старый — все itemIDs.
+	// Отсутствующие товары пропускаются.
+	StockChanges(ctx context.Context, since WatchCursor, itemIDs []int64, locationCode string) (StockChanges, error)
+}
+
+// ===== gRPC-ХЕНДЛЕР (StockService) =====
+
+func (s *Server) WatchStock(req *invpb.WatchStockRequest, stream grpc.ServerStreamingServer[invpb.StockEvent]) error {
+	ids := req.GetItemIds()
+	if l := len(ids); l == 0 {
+		return status.Error(codes.InvalidArgument, "item_ids is empty")
+	} else if l > s.maxBatch {
+		return status.Errorf(codes.InvalidArgument, "too many item_ids: %d > %d", l, s.maxBatch)
+	}
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return status.Error(codes.InvalidArgument, "item_id must be > 0")
+		}
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	cursor, err := decodeWatchToken(req.GetResumeToken())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid resume_token")
+	}
+	location := req.GetLocationCode()
+	ctx := stream.Context()
+
+	// Подписываемся ДО первого чтения: запись между чтением и подпиской не потеряется.
+	notify, cancel := s.w.Subscribe()
+	defer cancel()
+
+	for {
+		ch, err := s.w.StockChanges(ctx, cursor, unique, location)
+		if err != nil {
+			return internalf("watch stock: %v", err)
+		}
+		for i, st := range ch.Stocks {
+			// Токен двигаем только на последнем событии пачки: оборвались посередине —
+			// при переподключении пачка придёт заново целиком.
+			tok := cursor
+			if i == len(ch.Stocks)-1 {
+				tok = ch.Cursor
+			}
+			ev := &invpb.StockEvent{Stock: toPBStock(st, location), ResumeToken: encodeWatchToken(tok)}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+		cursor = ch.Cursor
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return status.FromContextError(ctx.Err()).Err()
+		case <-notify:
+		}
+	}
+}
+
+// ===== resume_token =====
+
+// Формат: base64url("<epoch>:<seq>"). Клиенту он непрозрачен.
+func encodeWatchToken(c WatchCursor) string {
+	if c.Epoch == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Epoch + ":" + strconv.FormatUint(c.Seq, 10)))
+}
+
+func decodeWatchToken(tok string) (WatchCursor, error) {
+	if tok == "" {
+		return WatchCursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return WatchCursor{}, err
+	}
+	epoch, seq, ok := strings.Cut(string(b), ":")
+	if !ok || epoch == "" {
+		return WatchCursor{}, errors.New("malformed token")
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return WatchCursor{}, err
+	}
+	return WatchCursor{Epoch: epoch, Seq: n}, nil
+}
@@ -0,0 +1,452 @@
+package filestore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/app"
+)
+
+// Файловое хранилище журнала остатков:
+//
+//	<dir>/snapshot.json     — последний снапшот (пишется атомарно через tmp + rename)
+//	<dir>/wal-000001.log    — сегменты журнала, по одной JSON-записи app.Entry на строку
+//
+// При старте: снапшот + все сегменты по порядку (записи с Seq <= snapshot.Seq пропускаются).
+// Снапшот: ротация сегмента -> срез состояния -> запись snapshot.json -> удаление старых сегментов.
+
+const (
+	snapshotFile = "snapshot.json"
+	walPrefix    = "wal-"
+	walSuffix    = ".log"
+)
+
+// SyncPolicy — когда делать fsync журнала.
+type SyncPolicy int
+
+const (
+	SyncAlways   SyncPolicy = iota // fsync на каждую запись (надёжно, медленно)
+	SyncInterval                   // fsync фоном раз в Options.SyncInterval
+	SyncNever                      // полагаемся на ОС
+)
+
+// ParseSyncPolicy — из строки конфига: "always" | "interval" | "never".
+func ParseSyncPolicy(s string) (SyncPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "always":
+		return SyncAlways, nil
+	case "interval":
+		return SyncInterval, nil
+	case "never":
+		return SyncNever, nil
+	default:
+		return SyncAlways, fmt.Errorf("unknown sync policy %q", s)
+	}
+}
+
+type Options struct {
+	Sync             SyncPolicy
+	SyncInterval     time.Duration // для SyncInterval; по умолчанию 100ms
+	SnapshotInterval time.Duration // период снапшотов в Run; 0 — только по SnapshotEvery
+	SnapshotEvery    int           // снапшот после N записей с прошлого; 0 — выключено
+
+	// SnapshotMovements — сколько последних движений хранит снапшот (старые отбрасываются,
+	// иначе файл растёт без предела); 0 — DefaultSnapshotMovements, < 0 — все.
+	SnapshotMovements int
+}
+
+// DefaultSnapshotMovements — история движений в снапшоте по умолчанию.
+const DefaultSnapshotMovements = 100_000
+
+// Store — реализация app.Ledger поверх файлов.
+type Store struct {
+	dir  string
+	opts Options
+
+	mu      sync.Mutex
+	wal     *os.File
+	w       *bufio.Writer
+	segment int   // номер текущего сегмента
+	size    int64 // байт в текущем сегменте, подтверждённых Append
+	dirty   bool  // есть записи без fsync
+	written int   // записей с последнего снапшота
+	failed  error // сбой записи: журнал мог разойтись с памятью, дальнейшие Append отклоняются
+
+	snapReq chan struct{}
+}
+
+var _ app.Ledger = (*Store)(nil)
+
+// Open создаёт каталог при необходимости и открывает новый сегмент журнала для дозаписи.
+// Существующие сегменты не трогаются — их читает Load.
+func Open(dir string, opts Options) (*Store, error) {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = 100 * time.Millisecond
+	}
+	if opts.SnapshotMovements == 0 {
+		opts.SnapshotMovements = DefaultSnapshotMovements
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("filestore: mkdir %s: %w", dir, err)
+	}
+	segs, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, opts: opts, snapReq: make(chan struct{}, 1)}
+	next := 1
+	if len(segs) > 0 {
+		next = segs[len(segs)-1] + 1
+	}
+	if err := s.openSegment(next); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load читает снапшот и все записи журнала (в порядке Seq) для app.Inventory.Restore.
+// Оборванная последняя строка сегмента (крэш посреди записи) игнорируется: каждый запуск
+// пишет в свой сегмент, поэтому такой «хвост» может остаться в любом из них.
+func (s *Store) Load() (app.Snapshot, []app.Entry, error) {
+	var snap app.Snapshot
+	b, err := os.ReadFile(filepath.Join(s.dir, snapshotFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return snap, nil, fmt.Errorf("filestore: read snapshot: %w", err)
+	default:
+		if err := json.Unmarshal(b, &snap); err != nil {
+			return snap, nil, fmt.Errorf("filestore: decode snapshot: %w", err)
+		}
+	}
+
+	segs, err := listSegments(s.dir)
+	if err != nil {
+		return snap, nil, err
+	}
+	var entries []app.Entry
+	for _, n := range segs {
+		if n == s.segment {
+			continue // текущий (только что открытый) сегмент пуст
+		}
+		es, err := readSegment(s.segmentPath(n))
+		if err != nil {
+			return snap, nil, err
+		}
+		entries = append(entries, es...)
+	}
+	return snap, entries, nil
+}
+
+// Append — app.Ledger: одна запись = одна строка; fsync по политике.
+// Сбой записи/flush/fsync — откат сегмента и fail-stop (см. failLocked).
+func (s *Store) Append(ctx context.Context, e app.Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("filestore: encode entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return errors.New("filestore: store is closed")
+	}
+	if s.failed != nil {
+		return fmt.Errorf("filestore: store failed earlier, restart required: %w", s.failed)
+	}
+	line := append(b, '\n')
+	if _, err := s.w.Write(line); err != nil {
+		return s.failLocked(fmt.Errorf("filestore: write wal: %w", err))
+	}
+	if err := s.w.Flush(); err != nil {
+		return s.failLocked(fmt.Errorf("filestore: flush wal: %w", err))
+	}
+	s.dirty = true
+	if s.opts.Sync == SyncAlways {
+		if err := s.syncLocked(); err != nil {
+			return s.failLocked(err)
+		}
+	}
+	s.size += int64(len(line))
+
+	s.written++
+	if s.opts.SnapshotEvery > 0 && s.written >= s.opts.SnapshotEvery {
+		select {
+		case s.snapReq <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run — фоновые задачи: периодический fsync (SyncInterval) и снапшоты.
+// snapshot должен возвращать консистентный срез (обычно app.Inventory.Snapshot).
+// Блокируется до отмены ctx.
+func (s *Store) Run(ctx context.Context, snapshot func() app.Snapshot) {
+	var syncC, snapC <-chan time.Time
+	if s.opts.Sync == SyncInterval {
+		t := time.NewTicker(s.opts.SyncInterval)
+		defer t.Stop()
+		syncC = t.C
+	}
+	if s.opts.SnapshotInterval > 0 {
+		t := time.NewTicker(s.opts.SnapshotInterval)
+		defer t.Stop()
+		snapC = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncC:
+			s.mu.Lock()
+			if err := s.syncLocked(); err != nil {
+				// Записи уже подтверждены вызывающим и могли не дойти до диска: fail-stop, как в Append.
+				s.stopLocked(err)
+			}
+			s.mu.Unlock()
+		case <-snapC:
+			s.snapshotAndLog(snapshot)
+		case <-s.snapReq:
+			s.snapshotAndLog(snapshot)
+		}
+	}
+}
+
+// Snapshot пишет снапшот и удаляет сегменты, целиком покрытые им.
+func (s *Store) Snapshot(snapshot func() app.Snapshot) error {
+	// 1) Ротация: всё, что придёт дальше, ляжет в новый сегмент.
+	s.mu.Lock()
+	if s.wal == nil {
+		s.mu.Unlock()
+		return errors.New("filestore: store is closed")
+	}
+	if s.failed != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("filestore: store failed earlier, restart required: %w", s.failed)
+	}
+	covered := s.segment
+	if err := s.syncLocked(); err != nil {
+		s.stopLocked(err)
+		s.mu.Unlock()
+		return err
+	}
+	if err := s.wal.Close(); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("filestore: close wal: %w", err)
+	}
+	if err := s.openSegment(covered + 1); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.written = 0
+	s.mu.Unlock()
+
+	// 2) Срез берём ПОСЛЕ ротации: он покрывает все записи старых сегментов.
+	// Из истории движений остаётся только хвост: ID следующих считаются от последнего, он сохранится.
+	snap := snapshot()
+	if n := s.opts.SnapshotMovements; n > 0 && len(snap.Movements) > n {
+		snap.Movements = snap.Movements[len(snap.Movements)-n:]
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, snapshotFile), snap); err != nil {
+		return err
+	}
+
+	// 3) Старые сегменты больше не нужны.
+	segs, err := listSegments(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, n := range segs {
+		if n <= covered {
+			if err := os.Remove(s.segmentPath(n)); err != nil {
+				return fmt.Errorf("filestore: remove segment: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Close — финальный fsync и закрытие сегмента.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.wal == nil {
+		return nil
+	}
+	err := s.syncLocked()
+	if cerr := s.wal.Close(); err == nil {
+		err = cerr
+	}
+	s.wal, s.w = nil, nil
+	return err
+}
+
+// ===== внутреннее =====
+
+func (s *Store) snapshotAndLog(snapshot func() app.Snapshot) {
+	if err := s.Snapshot(snapshot); err != nil {
+		log.Printf("filestore: snapshot: %v", err)
+	}
+}
+
+// failLocked — запись не подтверждена, а app её не применит: откатываем сегмент к последней
+// подтверждённой записи, чтобы replay не увидел её после рестарта, и останавливаем хранилище.
+// После сбоя fsync нельзя доверять ни буферу, ни page cache, поэтому fail-stop даже при
+// удачном откате; состояние восстановится рестартом (снапшот + журнал).
+func (s *Store) failLocked(err error) error {
+	if terr := s.wal.Truncate(s.size); terr != nil {
+		err = fmt.Errorf("%w; truncate back to %d: %v", err, s.size, terr)
+	} else if serr := s.wal.Sync(); serr != nil {
+		err = fmt.Errorf("%w; fsync after truncate: %v", err, serr)
+	}
+	s.stopLocked(err)
+	return err
+}
+
+// stopLocked — fail-stop без отката: дальнейшие Append и Snapshot отклоняются до рестарта.
+func (s *Store) stopLocked(err error) {
+	s.failed = err
+	log.Printf("%v (store stopped accepting writes)", err)
+}
+
+func (s *Store) syncLocked() error {
+	if !s.dirty || s.wal == nil {
+		return nil
+	}
+	if err := s.wal.Sync(); err != nil {
+		return fmt.Errorf("filestore: fsync wal: %w", err)
+	}
+	s.dirty = false
+	return nil
+}
+
+func (s *Store) openSegment(n int) error {
+	f, err := os.OpenFile(s.segmentPath(n), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("filestore: open segment: %w", err)
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("filestore: stat segment: %w", err)
+	}
+	s.wal, s.w, s.segment, s.size = f, bufio.NewWriter(f), n, st.Size()
+	return nil
+}
+
+func (s *Store) segmentPath(n int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%06d%s", walPrefix, n, walSuffix))
+}
+
+func listSegments(dir string) ([]int, error) {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("filestore: list %s: %w", dir, err)
+	}
+	var out []int
+	for _, de := range des {
+		name := de.Name()
+		if de.IsDir() || !strings.HasPrefix(name, walPrefix) || !strings.HasSuffix(name, walSuffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, walPrefix), walSuffix))
+		if err != nil {
+			continue
+		}
+		out = append(out, n)
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+// readSegment читает записи сегмента; битой может быть только последняя строка.
+func readSegment(path string) ([]app.Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("filestore: open segment: %w", err)
+	}
+	defer f.Close()
+
+	var out []app.Entry
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if len(b) > 0 {
+			var e app.Entry
+			if jerr := json.Unmarshal(b, &e); jerr != nil {
+				// Битая строка допустима только последней (запись оборвалась при крэше).
+				if err == io.EOF || isLast(r) {
+					log.Printf("filestore: %s:%d: dropping torn tail entry", filepath.Base(path), line)
+					return out, nil
+				}
+				return nil, fmt.Errorf("filestore: %s:%d: decode entry: %w", filepath.Base(path), line, jerr)
+			}
+			out = append(out, e)
+		}
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("filestore: read segment: %w", err)
+		}
+	}
+}
+
+func isLast(r *bufio.Reader) bool {
+	_, err := r.Peek(1)
+	return err == io.EOF
+}
+
+func writeFileAtomic(path string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("filestore: encode snapshot: %w", err)
+	}
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("filestore: create snapshot: %w", err)
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("filestore: write snapshot: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("filestore: fsync snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("filestore: close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("filestore: rename snapshot: %w", err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("filestore: open dir: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("filestore: fsync dir: %w", err)
+	}
+	return nil
+}
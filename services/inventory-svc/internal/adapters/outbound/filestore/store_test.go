@@ -0,0 +1,370 @@
+package filestore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+	"github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/app"
+)
+
+// ===== Хелперы =====
+
+// openInventory — как в main: Open, Load, Restore поверх журнала.
+func openInventory(t *testing.T, dir string, opts Options) (*Store, *app.Inventory) {
+	t.Helper()
+	s, err := Open(dir, opts)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	snap, entries, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	inv := app.NewInventory(app.WithLedger(s))
+	if err := inv.Restore(snap, entries); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	return s, inv
+}
+
+func createLocation(t *testing.T, inv *app.Inventory, code string) {
+	t.Helper()
+	if _, err := inv.CreateLocation(context.Background(), grpcstock.LocationDTO{Code: code, Status: grpcstock.LocationActive}); err != nil {
+		t.Fatalf("CreateLocation(%s): %v", code, err)
+	}
+}
+
+func adjust(inv *app.Inventory, itemID, delta int64) error {
+	_, err := inv.AdjustStock(context.Background(), grpcstock.AdjustCommand{
+		ItemID: itemID, LocationCode: "MSK-01", Delta: delta, Reason: grpcstock.ReasonReceipt,
+	})
+	return err
+}
+
+func mustAdjust(t *testing.T, inv *app.Inventory, itemID, delta int64) {
+	t.Helper()
+	if err := adjust(inv, itemID, delta); err != nil {
+		t.Fatalf("AdjustStock(%d, %+d): %v", itemID, delta, err)
+	}
+}
+
+// state — сравнимый срез: seq, остатки и версии по товарам, ID движений.
+func state(t *testing.T, inv *app.Inventory, itemIDs ...int64) string {
+	t.Helper()
+	ctx := context.Background()
+	var b strings.Builder
+	fmt.Fprintf(&b, "seq=%d", inv.Snapshot().Seq)
+	stocks, err := inv.BatchGetStock(ctx, itemIDs, "")
+	if err != nil {
+		t.Fatalf("BatchGetStock: %v", err)
+	}
+	for _, s := range stocks {
+		fmt.Fprintf(&b, " item=%d on_hand=%d reserved=%d", s.ItemID, s.OnHand, s.Reserved)
+		for _, l := range s.Locations {
+			fmt.Fprintf(&b, " [%s v%d %s]", l.LocationCode, l.Version, l.UpdatedAt.UTC().Format(time.RFC3339Nano))
+		}
+	}
+	page, err := inv.ListMovements(ctx, grpcstock.MovementFilter{Limit: 1000})
+	if err != nil {
+		t.Fatalf("ListMovements: %v", err)
+	}
+	for _, m := range page.Movements {
+		fmt.Fprintf(&b, " m%d", m.ID)
+	}
+	return b.String()
+}
+
+func lastSegment(t *testing.T, dir string) string {
+	t.Helper()
+	segs, err := listSegments(dir)
+	if err != nil || len(segs) == 0 {
+		t.Fatalf("listSegments: %v %v", segs, err)
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s%06d%s", walPrefix, segs[len(segs)-1], walSuffix))
+}
+
+// failWriter — диск, на который ничего не пишется.
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+// ===== Журнал =====
+
+func TestReopenRestoresState(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{})
+	createLocation(t, inv, "MSK-01")
+	mustAdjust(t, inv, 1, 10)
+	mustAdjust(t, inv, 2, 5)
+	mustAdjust(t, inv, 1, -3)
+	want := state(t, inv, 1, 2)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Каждый запуск пишет в свой сегмент: после второго рестарта replay идёт по двум.
+	s2, inv2 := openInventory(t, dir, Options{})
+	if got := state(t, inv2, 1, 2); got != want {
+		t.Fatalf("after reopen:\n got  %s\n want %s", got, want)
+	}
+	mustAdjust(t, inv2, 2, 1)
+	want = state(t, inv2, 1, 2)
+	if err := s2.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, inv3 := openInventory(t, dir, Options{})
+	if got := state(t, inv3, 1, 2); got != want {
+		t.Fatalf("after second reopen:\n got  %s\n want %s", got, want)
+	}
+}
+
+func TestLoadDropsTornTail(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{})
+	createLocation(t, inv, "MSK-01")
+	mustAdjust(t, inv, 1, 10)
+	want := state(t, inv, 1)
+	mustAdjust(t, inv, 1, 7)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Крэш посреди записи: от последней строки остались первые байты, без '\n'.
+	path := lastSegment(t, dir)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(string(b), "\n"), "\n")
+	torn := lines[len(lines)-1]
+	b = []byte(strings.Join(lines[:len(lines)-1], "") + torn[:len(torn)/2])
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, inv2 := openInventory(t, dir, Options{})
+	if got := state(t, inv2, 1); got != want {
+		t.Fatalf("after torn tail:\n got  %s\n want %s", got, want)
+	}
+}
+
+func TestLoadRejectsCorruptMiddleEntry(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{})
+	createLocation(t, inv, "MSK-01")
+	mustAdjust(t, inv, 1, 10)
+	mustAdjust(t, inv, 1, 1)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	path := lastSegment(t, dir)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	lines[1] = "{garbage\n"
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := Open(dir, Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s2.Close()
+	if _, _, err := s2.Load(); err == nil {
+		t.Fatal("Load: want error for a corrupt entry in the middle of a segment")
+	}
+}
+
+// ===== Снапшоты =====
+
+func TestSnapshotRotatesAndReplays(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{SnapshotMovements: 2})
+	createLocation(t, inv, "MSK-01")
+	for i := range 5 {
+		mustAdjust(t, inv, 1, int64(i+1))
+	}
+	covered := s.segment
+	if err := s.Snapshot(inv.Snapshot); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if _, err := os.Stat(s.segmentPath(covered)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("segment %d covered by snapshot is still there: %v", covered, err)
+	}
+	if s.segment != covered+1 {
+		t.Fatalf("segment after snapshot = %d, want %d", s.segment, covered+1)
+	}
+
+	var snap app.Snapshot
+	b, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &snap); err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Movements) != 2 || snap.Movements[1].ID != 5 {
+		t.Fatalf("snapshot movements = %+v, want the last 2 (IDs 4, 5)", snap.Movements)
+	}
+
+	// После снапшота — ещё записи в новый сегмент: replay = снапшот + хвост.
+	mustAdjust(t, inv, 1, 100)
+	mustAdjust(t, inv, 2, 1)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, inv2 := openInventory(t, dir, Options{})
+	stocks, err := inv2.BatchGetStock(context.Background(), []int64{1, 2}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stocks) != 2 || stocks[0].OnHand != 115 || stocks[1].OnHand != 1 {
+		t.Fatalf("stocks after snapshot + replay = %+v", stocks)
+	}
+	// ID движений продолжаются от последнего в снапшоте.
+	page, err := inv2.ListMovements(context.Background(), grpcstock.MovementFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, m := range page.Movements {
+		ids = append(ids, m.ID)
+	}
+	if fmt.Sprint(ids) != "[7 6 5 4]" {
+		t.Fatalf("movement IDs = %v, want [7 6 5 4]", ids)
+	}
+}
+
+// ===== Fail-stop =====
+
+func TestWriteFailureStopsStore(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{})
+	createLocation(t, inv, "MSK-01")
+	mustAdjust(t, inv, 1, 10)
+	want := state(t, inv, 1)
+
+	s.mu.Lock()
+	s.w = bufio.NewWriter(failWriter{})
+	s.mu.Unlock()
+	if err := adjust(inv, 1, 5); err == nil {
+		t.Fatal("AdjustStock: want error when the WAL write fails")
+	}
+	if got := state(t, inv, 1); got != want {
+		t.Fatalf("memory changed after a failed append:\n got  %s\n want %s", got, want)
+	}
+
+	// Диск «починился», но хранилище остаётся остановленным до рестарта.
+	s.mu.Lock()
+	s.w = bufio.NewWriter(s.wal)
+	s.mu.Unlock()
+	if err := adjust(inv, 1, 1); err == nil {
+		t.Fatal("AdjustStock: want error after the store failed")
+	}
+	if err := s.Snapshot(inv.Snapshot); err == nil {
+		t.Fatal("Snapshot: want error after the store failed")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, inv2 := openInventory(t, dir, Options{})
+	if got := state(t, inv2, 1); got != want {
+		t.Fatalf("after restart:\n got  %s\n want %s", got, want)
+	}
+}
+
+func TestPartialWriteIsRolledBack(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{})
+	createLocation(t, inv, "MSK-01")
+	mustAdjust(t, inv, 1, 10)
+	want := state(t, inv, 1)
+
+	// Половина строки дошла до файла, потом ошибка: сегмент откатывается к подтверждённой длине.
+	s.mu.Lock()
+	s.w = bufio.NewWriterSize(&halfWriter{f: s.wal}, 16)
+	s.mu.Unlock()
+	if err := adjust(inv, 1, 5); err == nil {
+		t.Fatal("AdjustStock: want error on a partial write")
+	}
+	st, err := os.Stat(lastSegment(t, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Size() != s.size {
+		t.Fatalf("segment size = %d, want %d (rolled back)", st.Size(), s.size)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, inv2 := openInventory(t, dir, Options{})
+	if got := state(t, inv2, 1); got != want {
+		t.Fatalf("after restart:\n got  %s\n want %s", got, want)
+	}
+}
+
+// halfWriter пишет половину первого буфера и падает.
+type halfWriter struct{ f *os.File }
+
+func (w *halfWriter) Write(p []byte) (int, error) {
+	n, _ := w.f.Write(p[:len(p)/2])
+	return n, errors.New("disk full")
+}
+
+func TestBackgroundSyncFailureStopsStore(t *testing.T) {
+	dir := t.TempDir()
+	s, inv := openInventory(t, dir, Options{Sync: SyncInterval, SyncInterval: time.Millisecond})
+	createLocation(t, inv, "MSK-01")
+
+	// Записи подтверждены, fsync ещё не было — и тут fsync начинает падать.
+	s.mu.Lock()
+	s.dirty = true
+	_ = s.wal.Close()
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, inv.Snapshot)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		failed := s.failed
+		s.mu.Unlock()
+		if failed != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("store did not stop after a failed background fsync")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := adjust(inv, 1, 1); err == nil {
+		t.Fatal("AdjustStock: want error after a failed background fsync")
+	}
+}
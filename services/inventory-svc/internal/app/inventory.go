@@ -0,0 +1,490 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// Inventory — реализация портов InventoryQueries и InventoryCommands поверх in-memory хранилища.
+// Ключ остатка — (item_id, location_code). Безопасно для конкурентного использования.
+type Inventory struct {
+	mu           sync.RWMutex
+	items        map[int64]map[string]*level // item_id -> location_code -> остаток
+	movements    []Movement                  // журнал движений, по возрастанию ID
+	reservations map[string]*Reservation     // reservation_id -> резерв
+	locations    map[string]*Location        // реестр: location_code -> локация
+	transfers    map[string]*Transfer        // transfer_id -> перемещение
+	seq          uint64                      // номер последней применённой записи журнала
+	ledger       Ledger                      // nil — чисто in-memory режим
+	now          func() time.Time
+	watch        watchState
+}
+
+// level — остаток по одной локации. Доступно к продаже: onHand - reserved.
+type level struct {
+	onHand    int64
+	reserved  int64     // сумма активных резервов
+	inTransit int64     // едет сюда по TransferStock, ещё не принято (в onHand не входит)
+	updatedAt time.Time // строго растёт при каждом изменении onHand
+	version   int64     // 1, 2, 3... — номер изменения onHand
+}
+
+// withOnHand — следующее состояние локации: версия +1, время не меньше предыдущего + 1ns,
+// чтобы prev_updated_at однозначно различал две записи даже при грубых часах.
+// Резервы и товар в пути версию не двигают: оптимистическая блокировка защищает именно onHand.
+func (l level) withOnHand(onHand int64, now time.Time) level {
+	if !now.After(l.updatedAt) {
+		now = l.updatedAt.Add(time.Nanosecond)
+	}
+	l.onHand, l.updatedAt, l.version = onHand, now, l.version+1
+	return l
+}
+
+var (
+	_ grpcstock.InventoryQueries  = (*Inventory)(nil)
+	_ grpcstock.InventoryCommands = (*Inventory)(nil)
+)
+
+// Option — функциональная опция конструктора Inventory.
+type Option func(*Inventory)
+
+// WithLedger — писать каждое изменение в журнал до применения (для персистентности).
+func WithLedger(l Ledger) Option {
+	return func(inv *Inventory) { inv.ledger = l }
+}
+
+func NewInventory(opts ...Option) *Inventory {
+	inv := &Inventory{
+		items:        make(map[int64]map[string]*level),
+		reservations: make(map[string]*Reservation),
+		locations:    make(map[string]*Location),
+		transfers:    make(map[string]*Transfer),
+		now:          time.Now,
+		watch:        newWatchState(),
+	}
+	for _, o := range opts {
+		o(inv)
+	}
+	return inv
+}
+
+// ===== Queries =====
+
+func (inv *Inventory) GetStock(ctx context.Context, itemID int64, locationCode string) (grpcstock.StockDTO, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	locs, ok := inv.items[itemID]
+	if !ok {
+		return grpcstock.StockDTO{}, errorsx.NotFoundf("item %d", itemID)
+	}
+	return toDTO(itemID, locs), nil
+}
+
+func (inv *Inventory) BatchGetStock(ctx context.Context, itemIDs []int64, locationCode string) ([]grpcstock.StockDTO, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	out := make([]grpcstock.StockDTO, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		if locs, ok := inv.items[id]; ok {
+			out = append(out, toDTO(id, locs))
+		}
+	}
+	return out, nil
+}
+
+// ===== Commands =====
+
+func (inv *Inventory) AdjustStock(ctx context.Context, cmd grpcstock.AdjustCommand) (grpcstock.StockDTO, error) {
+	stocks, err := inv.BatchAdjustStock(ctx, []grpcstock.AdjustCommand{cmd})
+	if err != nil {
+		return grpcstock.StockDTO{}, err
+	}
+	return stocks[0], nil
+}
+
+func (inv *Inventory) SetStock(ctx context.Context, cmd grpcstock.SetCommand) (grpcstock.StockDTO, error) {
+	if cmd.NewAvailable < 0 {
+		return grpcstock.StockDTO{}, errorsx.InvalidArgumentf("new_available must be >= 0, got %d", cmd.NewAvailable)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if err := inv.checkLocation("location_code", cmd.LocationCode); err != nil {
+		return grpcstock.StockDTO{}, err
+	}
+	if err := inv.checkPrecondition(cmd.ItemID, cmd.LocationCode, cmd.Precondition); err != nil {
+		return grpcstock.StockDTO{}, err
+	}
+	cur := inv.level(cmd.ItemID, cmd.LocationCode)
+	// Как в AdjustStock: остаток ниже зарезервированного увёл бы available в минус.
+	if cmd.NewAvailable < cur.reserved {
+		return grpcstock.StockDTO{}, errorsx.Wrap(errorsx.KindFailedPrecondition, "BELOW_RESERVED", false,
+			[]errorsx.Violation{{
+				Field:   "new_available",
+				Code:    "BELOW_RESERVED",
+				Message: "new_available must not be below the reserved quantity",
+				Params:  map[string]string{"reserved": strconv.FormatInt(cur.reserved, 10)},
+			}},
+			fmt.Errorf("item %d at %q: available stock would become negative (set %d, reserved %d)",
+				cmd.ItemID, cmd.LocationCode, cmd.NewAvailable, cur.reserved))
+	}
+	next := cur.withOnHand(cmd.NewAvailable, inv.now())
+	err := inv.commit(ctx, Entry{
+		Changes: []Change{toChange(cmd.ItemID, cmd.LocationCode, next)},
+		Movements: []Movement{{
+			ItemID:       cmd.ItemID,
+			LocationCode: cmd.LocationCode,
+			Before:       cur.onHand,
+			After:        next.onHand,
+			Reason:       cmd.Reason,
+			Reference:    cmd.Reference,
+			Actor:        cmd.Actor,
+			At:           next.updatedAt,
+		}},
+	})
+	if err != nil {
+		return grpcstock.StockDTO{}, err
+	}
+	return toDTO(cmd.ItemID, inv.items[cmd.ItemID]), nil
+}
+
+// BatchAdjustStock применяет строки атомарно: сначала все строки проверяются на «черновике»,
+// и только если ни одна не упала — результат записывается в хранилище.
+func (inv *Inventory) BatchAdjustStock(ctx context.Context, lines []grpcstock.AdjustCommand) ([]grpcstock.StockDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	b := inv.stageAdjust(lines)
+	for i, err := range b.errs {
+		if err != nil {
+			return nil, lineErr(len(lines), i, err)
+		}
+	}
+	if err := inv.commit(ctx, b.entry()); err != nil {
+		return nil, err
+	}
+
+	out := make([]grpcstock.StockDTO, 0, len(lines))
+	for _, ln := range lines {
+		out = append(out, toDTO(ln.ItemID, inv.items[ln.ItemID]))
+	}
+	return out, nil
+}
+
+// BatchAdjustStockPartial применяет прошедшие проверки строки одной записью журнала,
+// упавшие пропускает и возвращает их ошибки по месту.
+func (inv *Inventory) BatchAdjustStockPartial(ctx context.Context, lines []grpcstock.AdjustCommand) ([]grpcstock.AdjustLineResult, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	b := inv.stageAdjust(lines)
+	if len(b.moves) > 0 {
+		if err := inv.commit(ctx, b.entry()); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]grpcstock.AdjustLineResult, len(lines))
+	for i, ln := range lines {
+		if b.errs[i] != nil {
+			out[i].Err = b.errs[i]
+			continue
+		}
+		out[i].Stock = toDTO(ln.ItemID, inv.items[ln.ItemID])
+	}
+	return out, nil
+}
+
+// ===== Персистентность =====
+
+// Snapshot — консистентный срез всех остатков вместе с номером последней записи журнала.
+func (inv *Inventory) Snapshot() Snapshot {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	snap := Snapshot{Seq: inv.seq, Movements: slices.Clone(inv.movements)}
+	for itemID, locs := range inv.items {
+		for code, l := range locs {
+			snap.Levels = append(snap.Levels, toChange(itemID, code, *l))
+		}
+	}
+	for _, r := range inv.reservations {
+		snap.Reservations = append(snap.Reservations, *r)
+	}
+	for _, l := range inv.locations {
+		snap.Locations = append(snap.Locations, *l)
+	}
+	for _, t := range inv.transfers {
+		snap.Transfers = append(snap.Transfers, *t)
+	}
+	return snap
+}
+
+// Restore заменяет состояние снапшотом и доигрывает поверх него записи журнала.
+// Записи с Seq <= snap.Seq пропускаются. В ledger при этом ничего не пишется.
+func (inv *Inventory) Restore(snap Snapshot, entries []Entry) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	inv.items = make(map[int64]map[string]*level)
+	inv.movements = nil
+	inv.reservations = make(map[string]*Reservation)
+	inv.locations = make(map[string]*Location)
+	inv.transfers = make(map[string]*Transfer)
+	inv.seq = snap.Seq
+	inv.apply(Entry{
+		Changes:      snap.Levels,
+		Movements:    snap.Movements,
+		Reservations: snap.Reservations,
+		Locations:    snap.Locations,
+		Transfers:    snap.Transfers,
+	})
+	for _, e := range entries {
+		if e.Seq <= inv.seq {
+			continue
+		}
+		if e.Seq != inv.seq+1 {
+			return errorsx.Internalf("ledger gap: expected seq %d, got %d", inv.seq+1, e.Seq)
+		}
+		inv.apply(e)
+		inv.seq = e.Seq
+	}
+	// История изменений до рестарта не известна: подписчики со старым курсором получат всё заново.
+	inv.watch.reset(inv.seq)
+	return nil
+}
+
+// ===== внутреннее =====
+
+// commit — единая точка записи: журнал (если есть), затем память. Вызывать под inv.mu.Lock.
+// Seq и ID движений присваиваются здесь — до записи в ledger, чтобы при replay они совпали.
+func (inv *Inventory) commit(ctx context.Context, e Entry) error {
+	lastID := int64(0)
+	if n := len(inv.movements); n > 0 {
+		lastID = inv.movements[n-1].ID
+	}
+	for i := range e.Movements {
+		e.Movements[i].ID = lastID + int64(i) + 1
+	}
+	e.Seq = inv.seq + 1
+
+	if inv.ledger != nil {
+		if err := inv.ledger.Append(ctx, e); err != nil {
+			return errorsx.Internalf("ledger append: %v", err)
+		}
+	}
+	inv.apply(e)
+	inv.seq = e.Seq
+	inv.watch.record(e)
+	return nil
+}
+
+func (inv *Inventory) apply(e Entry) {
+	for _, c := range e.Changes {
+		inv.store(c.ItemID, c.LocationCode, level{
+			onHand:    c.OnHand,
+			reserved:  c.Reserved,
+			inTransit: c.InTransit,
+			updatedAt: c.UpdatedAt,
+			version:   c.Version,
+		})
+	}
+	inv.movements = append(inv.movements, e.Movements...)
+	for _, r := range e.Reservations {
+		inv.reservations[r.ID] = &r
+	}
+	for _, id := range e.PurgedReservations {
+		delete(inv.reservations, id)
+	}
+	for _, l := range e.Locations {
+		inv.locations[l.Code] = &l
+	}
+	for _, t := range e.Transfers {
+		inv.transfers[t.ID] = &t
+	}
+	for _, code := range e.DeletedLocations {
+		delete(inv.locations, code)
+		// Удаляют только пустые локации — нулевые строки остатка больше не показываем.
+		for _, locs := range inv.items {
+			delete(locs, code)
+		}
+	}
+}
+
+// adjustBatch — «черновик» батча: итоговые остатки по ключам, движения и ошибки по строкам.
+type adjustBatch struct {
+	staged map[stockKey]level
+	order  []stockKey
+	moves  []Movement
+	errs   []error // nil — строка применима
+}
+
+// stageAdjust проверяет строки по порядку; каждая следующая видит результат предыдущих
+// применимых строк. Упавшая строка на черновик не влияет. Вызывать под inv.mu.Lock.
+func (inv *Inventory) stageAdjust(lines []grpcstock.AdjustCommand) adjustBatch {
+	now := inv.now()
+	b := adjustBatch{
+		staged: make(map[stockKey]level, len(lines)),
+		order:  make([]stockKey, 0, len(lines)),
+		moves:  make([]Movement, 0, len(lines)),
+		errs:   make([]error, len(lines)),
+	}
+	for i, ln := range lines {
+		k := stockKey{ln.ItemID, ln.LocationCode}
+		cur, ok := b.staged[k]
+		if !ok {
+			if err := inv.checkLocation("location_code", ln.LocationCode); err != nil {
+				b.errs[i] = err
+				continue
+			}
+			// prev_updated_at / expected_version сверяем с состоянием ДО батча.
+			if err := inv.checkPrecondition(ln.ItemID, ln.LocationCode, ln.Precondition); err != nil {
+				b.errs[i] = err
+				continue
+			}
+			cur = inv.level(ln.ItemID, ln.LocationCode)
+		}
+		next := cur.onHand + ln.Delta
+		// Зарезервированное трогать нельзя: available = onHand - reserved не должен уйти в минус.
+		if next-cur.reserved < 0 && !ln.AllowNegative {
+			b.errs[i] = errorsx.FailedPreconditionf(
+				"item %d at %q: available stock would become negative (%d%+d, reserved %d)",
+				ln.ItemID, ln.LocationCode, cur.onHand, ln.Delta, cur.reserved)
+			continue
+		}
+		if !ok {
+			b.order = append(b.order, k)
+		}
+		b.staged[k] = cur.withOnHand(next, now)
+		b.moves = append(b.moves, Movement{
+			ItemID:       ln.ItemID,
+			LocationCode: ln.LocationCode,
+			Before:       cur.onHand,
+			After:        next,
+			Reason:       ln.Reason,
+			Reference:    ln.Reference,
+			Actor:        ln.Actor,
+			At:           b.staged[k].updatedAt,
+		})
+	}
+	return b
+}
+
+func (b adjustBatch) entry() Entry {
+	changes := make([]Change, 0, len(b.order))
+	for _, k := range b.order {
+		changes = append(changes, toChange(k.itemID, k.location, b.staged[k]))
+	}
+	return Entry{Changes: changes, Movements: b.moves}
+}
+
+type stockKey struct {
+	itemID   int64
+	location string
+}
+
+func (inv *Inventory) lookup(itemID int64, location string) *level {
+	return inv.items[itemID][location]
+}
+
+// level — копия остатка по локации; нулевой level, если записи ещё нет.
+func (inv *Inventory) level(itemID int64, location string) level {
+	if l := inv.lookup(itemID, location); l != nil {
+		return *l
+	}
+	return level{}
+}
+
+func (inv *Inventory) store(itemID int64, location string, l level) {
+	locs, ok := inv.items[itemID]
+	if !ok {
+		locs = make(map[string]*level)
+		inv.items[itemID] = locs
+	}
+	locs[location] = &l
+}
+
+// checkPrecondition — оптимистическая блокировка по локации (время — с точностью до наносекунд).
+// При несовпадении возвращает VersionConflictError с актуальным состоянием товара.
+func (inv *Inventory) checkPrecondition(itemID int64, location string, pre grpcstock.Precondition) error {
+	if pre.PrevUpdatedAt.IsZero() && pre.ExpectedVersion == 0 {
+		return nil
+	}
+	cur := inv.level(itemID, location)
+	var reason string
+	switch {
+	case pre.ExpectedVersion != 0 && pre.ExpectedVersion != cur.version:
+		reason = fmt.Sprintf("expected_version mismatch (current %d, got %d)", cur.version, pre.ExpectedVersion)
+	case !pre.PrevUpdatedAt.IsZero() && !pre.PrevUpdatedAt.Equal(cur.updatedAt):
+		reason = fmt.Sprintf("prev_updated_at mismatch (current %s, got %s)",
+			cur.updatedAt.UTC().Format(time.RFC3339Nano), pre.PrevUpdatedAt.UTC().Format(time.RFC3339Nano))
+	default:
+		return nil
+	}
+	return grpcstock.NewVersionConflict(toDTO(itemID, inv.items[itemID]), location, reason)
+}
+
+func toChange(itemID int64, location string, l level) Change {
+	return Change{
+		ItemID:       itemID,
+		LocationCode: location,
+		OnHand:       l.onHand,
+		Reserved:     l.reserved,
+		InTransit:    l.inTransit,
+		UpdatedAt:    l.updatedAt,
+		Version:      l.version,
+	}
+}
+
+// lineErr добавляет номер строки к ошибке батча; для одиночной команды оставляет как есть.
+// У нарушений по полям номер строки уходит в путь поля: "lines.3.location_code".
+func lineErr(total, i int, err error) error {
+	if total == 1 {
+		return err
+	}
+	if ve, ok := errorsx.AsValidation(err); ok {
+		return ve.WithPrefix(fmt.Sprintf("lines.%d", i))
+	}
+	return fmt.Errorf("lines[%d]: %w", i, err)
+}
+
+func toDTO(itemID int64, locs map[string]*level) grpcstock.StockDTO {
+	dto := grpcstock.StockDTO{
+		ItemID:    itemID,
+		Locations: make([]grpcstock.StockPerLocationDTO, 0, len(locs)),
+	}
+	for code, l := range locs {
+		dto.OnHand += l.onHand
+		dto.Reserved += l.reserved
+		dto.InTransit += l.inTransit
+		if l.updatedAt.After(dto.UpdatedAt) {
+			dto.UpdatedAt = l.updatedAt
+		}
+		dto.Locations = append(dto.Locations, grpcstock.StockPerLocationDTO{
+			LocationCode: code,
+			Available:    l.onHand - l.reserved,
+			Reserved:     l.reserved,
+			OnHand:       l.onHand,
+			InTransit:    l.inTransit,
+			UpdatedAt:    l.updatedAt,
+			Version:      l.version,
+		})
+	}
+	dto.Available = dto.OnHand - dto.Reserved
+	// Стабильный порядок локаций для клиентов и логов.
+	sort.Slice(dto.Locations, func(i, j int) bool {
+		return dto.Locations[i].LocationCode < dto.Locations[j].LocationCode
+	})
+	return dto
+}
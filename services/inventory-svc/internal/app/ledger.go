@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// Ledger — выходной порт журнала изменений (write-ahead log).
+// Inventory пишет туда каждую атомарную операцию ДО применения в памяти;
+// если Append вернул ошибку — состояние в памяти не меняется.
+type Ledger interface {
+	Append(ctx context.Context, e Entry) error
+}
+
+// Entry — одна атомарная запись журнала (одиночная команда или батч целиком).
+type Entry struct {
+	Seq          uint64        `json:"seq"`
+	Changes      []Change      `json:"changes,omitempty"`
+	Movements    []Movement    `json:"movements,omitempty"`
+	Reservations []Reservation `json:"reservations,omitempty"` // новое состояние резервов (upsert по ID)
+	// Забытые завершённые резервы (срок хранения истёк) — иначе replay вернул бы их.
+	PurgedReservations []string `json:"purged_reservations,omitempty"`
+	// Реестр локаций: новое состояние (upsert по Code) и удалённые коды.
+	Locations        []Location `json:"locations,omitempty"`
+	DeletedLocations []string   `json:"deleted_locations,omitempty"`
+	Transfers        []Transfer `json:"transfers,omitempty"` // upsert по ID
+}
+
+// Change — итоговое значение остатка по локации ПОСЛЕ изменения.
+// Храним абсолютные значения, а не дельты: повторное применение записи идемпотентно.
+type Change struct {
+	ItemID       int64     `json:"item_id"`
+	LocationCode string    `json:"location_code"`
+	OnHand       int64     `json:"on_hand"`
+	Reserved     int64     `json:"reserved"`
+	InTransit    int64     `json:"in_transit,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"` // RFC 3339 с наносекундами
+	Version      int64     `json:"version"`
+}
+
+// Snapshot — полное состояние остатков на момент записи Seq.
+type Snapshot struct {
+	Seq          uint64        `json:"seq"`
+	Levels       []Change      `json:"levels"`
+	Movements    []Movement    `json:"movements,omitempty"`
+	Reservations []Reservation `json:"reservations,omitempty"`
+	Locations    []Location    `json:"locations,omitempty"`
+	Transfers    []Transfer    `json:"transfers,omitempty"`
+}
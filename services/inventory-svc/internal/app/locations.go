@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// locationCodeRe — код локации: префикс города/сети и номер, например "MSK-01", "SPB-0012".
+var locationCodeRe = regexp.MustCompile(`^[A-Z]{2,8}-[0-9]{2,4}$`)
+
+const (
+	maxLocationName = 128
+	maxLocationCity = 64
+)
+
+// Location — запись реестра. Пишется в журнал целиком (upsert по Code).
+type Location struct {
+	Code      string                   `json:"code"`
+	Name      string                   `json:"name,omitempty"`
+	City      string                   `json:"city,omitempty"`
+	Type      grpcstock.LocationType   `json:"type,omitempty"`
+	Status    grpcstock.LocationStatus `json:"status"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+var _ grpcstock.LocationRegistry = (*Inventory)(nil)
+
+func (inv *Inventory) CreateLocation(ctx context.Context, in grpcstock.LocationDTO) (grpcstock.LocationDTO, error) {
+	loc := Location{Code: in.Code, Name: in.Name, City: in.City, Type: in.Type, Status: in.Status}
+	if ve := validateLocation(loc); !ve.IsEmpty() {
+		return grpcstock.LocationDTO{}, ve
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if _, ok := inv.locations[loc.Code]; ok {
+		return grpcstock.LocationDTO{}, errorsx.AlreadyExistsf("location %q", loc.Code)
+	}
+	loc.CreatedAt = inv.now()
+	loc.UpdatedAt = loc.CreatedAt
+	if err := inv.commit(ctx, Entry{Locations: []Location{loc}}); err != nil {
+		return grpcstock.LocationDTO{}, err
+	}
+	return toLocationDTO(loc), nil
+}
+
+func (inv *Inventory) GetLocation(ctx context.Context, code string) (grpcstock.LocationDTO, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	loc, ok := inv.locations[code]
+	if !ok {
+		return grpcstock.LocationDTO{}, errorsx.NotFoundf("location %q", code)
+	}
+	return toLocationDTO(*loc), nil
+}
+
+func (inv *Inventory) ListLocations(ctx context.Context, f grpcstock.LocationFilter) ([]grpcstock.LocationDTO, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	out := make([]grpcstock.LocationDTO, 0, len(inv.locations))
+	for _, l := range inv.locations {
+		switch {
+		case f.Status != grpcstock.LocationStatusUnspecified && l.Status != f.Status:
+			continue
+		case f.Type != grpcstock.LocationTypeUnspecified && l.Type != f.Type:
+			continue
+		case f.City != "" && l.City != f.City:
+			continue
+		}
+		out = append(out, toLocationDTO(*l))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out, nil
+}
+
+func (inv *Inventory) UpdateLocation(ctx context.Context, p grpcstock.LocationPatch) (grpcstock.LocationDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	cur, ok := inv.locations[p.Code]
+	if !ok {
+		return grpcstock.LocationDTO{}, errorsx.NotFoundf("location %q", p.Code)
+	}
+	loc := *cur
+	if p.Name != nil {
+		loc.Name = *p.Name
+	}
+	if p.City != nil {
+		loc.City = *p.City
+	}
+	if p.Type != nil {
+		loc.Type = *p.Type
+	}
+	if p.Status != nil {
+		loc.Status = *p.Status
+	}
+	if ve := validateLocation(loc); !ve.IsEmpty() {
+		return grpcstock.LocationDTO{}, ve
+	}
+	if loc == *cur {
+		return toLocationDTO(loc), nil
+	}
+	loc.UpdatedAt = inv.now()
+	if err := inv.commit(ctx, Entry{Locations: []Location{loc}}); err != nil {
+		return grpcstock.LocationDTO{}, err
+	}
+	return toLocationDTO(loc), nil
+}
+
+func (inv *Inventory) DeleteLocation(ctx context.Context, code string) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if _, ok := inv.locations[code]; !ok {
+		return errorsx.NotFoundf("location %q", code)
+	}
+	for itemID, locs := range inv.items {
+		if l, ok := locs[code]; ok && (l.onHand != 0 || l.reserved != 0 || l.inTransit != 0) {
+			return errorsx.FailedPreconditionf(
+				"location %q still holds stock of item %d (on_hand %d, reserved %d, in_transit %d); deactivate it instead",
+				code, itemID, l.onHand, l.reserved, l.inTransit)
+		}
+	}
+	// Отмена перемещения вернёт товар на источник: пока оно в пути, источник нужен.
+	for _, t := range inv.transfers {
+		if t.State == grpcstock.TransferInTransit && t.From == code {
+			return errorsx.FailedPreconditionf(
+				"location %q is the source of in-transit transfer %s; receive or cancel it first", code, t.ID)
+		}
+	}
+	return inv.commit(ctx, Entry{DeletedLocations: []string{code}})
+}
+
+// ===== внутреннее =====
+
+// checkLocation — менять остаток можно только в зарегистрированной активной локации.
+// field — имя поля запроса для нарушения ("location_code", "to_location_code", ...).
+func (inv *Inventory) checkLocation(field, code string) error {
+	loc, ok := inv.locations[code]
+	switch {
+	case !ok:
+		return errorsx.NewValidation().Add(field, "LOCATION_UNKNOWN",
+			fmt.Sprintf("location %q is not registered", code), nil)
+	case loc.Status != grpcstock.LocationActive:
+		return errorsx.NewValidation().Add(field, "LOCATION_INACTIVE",
+			fmt.Sprintf("location %q is inactive", code), nil)
+	}
+	return nil
+}
+
+func validateLocation(l Location) *errorsx.ValidationError {
+	ve := errorsx.NewValidation()
+	switch {
+	case l.Code == "":
+		ve.Add("code", "REQUIRED", "code is required", nil)
+	case !locationCodeRe.MatchString(l.Code):
+		ve.Add("code", "INVALID_FORMAT", "code must look like MSK-01", map[string]string{"pattern": locationCodeRe.String()})
+	}
+	if n := utf8.RuneCountInString(l.Name); n > maxLocationName {
+		ve.Add("name", "TOO_LONG", "name is too long", map[string]string{"max": strconv.Itoa(maxLocationName)})
+	}
+	if n := utf8.RuneCountInString(l.City); n > maxLocationCity {
+		ve.Add("city", "TOO_LONG", "city is too long", map[string]string{"max": strconv.Itoa(maxLocationCity)})
+	}
+	return ve
+}
+
+func toLocationDTO(l Location) grpcstock.LocationDTO {
+	return grpcstock.LocationDTO{
+		Code:      l.Code,
+		Name:      l.Name,
+		City:      l.City,
+		Type:      l.Type,
+		Status:    l.Status,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
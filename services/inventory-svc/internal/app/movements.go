@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// Movement — запись журнала движений: кто, когда и почему изменил остаток по локации.
+// Пишется в Entry вместе с изменением остатка, поэтому журнал так же долговечен, как и сами остатки.
+type Movement struct {
+	ID           int64                  `json:"id"`
+	ItemID       int64                  `json:"item_id"`
+	LocationCode string                 `json:"location_code"`
+	Before       int64                  `json:"before"`
+	After        int64                  `json:"after"`
+	Reason       grpcstock.ChangeReason `json:"reason"`
+	Reference    string                 `json:"reference,omitempty"`
+	Actor        string                 `json:"actor,omitempty"`
+	At           time.Time              `json:"at"`
+}
+
+var _ grpcstock.MovementQueries = (*Inventory)(nil)
+
+// ListMovements — от новых к старым, с фильтрами и курсором по ID.
+func (inv *Inventory) ListMovements(ctx context.Context, f grpcstock.MovementFilter) (grpcstock.MovementPage, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	var page grpcstock.MovementPage
+	for i := len(inv.movements) - 1; i >= 0; i-- {
+		m := inv.movements[i]
+		if f.BeforeID > 0 && m.ID >= f.BeforeID {
+			continue
+		}
+		if !matchMovement(m, f) {
+			continue
+		}
+		if len(page.Movements) == f.Limit {
+			page.HasMore = true
+			break
+		}
+		page.Movements = append(page.Movements, toMovementDTO(m))
+	}
+	return page, nil
+}
+
+func matchMovement(m Movement, f grpcstock.MovementFilter) bool {
+	switch {
+	case f.ItemID != 0 && m.ItemID != f.ItemID:
+		return false
+	case f.LocationCode != "" && m.LocationCode != f.LocationCode:
+		return false
+	case f.Reference != "" && m.Reference != f.Reference:
+		return false
+	case len(f.Reasons) > 0 && !slices.Contains(f.Reasons, m.Reason):
+		return false
+	case !f.Since.IsZero() && m.At.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !m.At.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+func toMovementDTO(m Movement) grpcstock.MovementDTO {
+	return grpcstock.MovementDTO{
+		ID:           m.ID,
+		ItemID:       m.ItemID,
+		LocationCode: m.LocationCode,
+		Delta:        m.After - m.Before,
+		Before:       m.Before,
+		After:        m.After,
+		Reason:       m.Reason,
+		Reference:    m.Reference,
+		Actor:        m.Actor,
+		CreatedAt:    m.At,
+	}
+}
+
+// BatchGetStockAsOf восстанавливает on_hand по журналу: на каждую локацию — After последнего
+// движения не позже asOf. version = число таких движений (каждое изменение onHand пишет ровно одно).
+// Резервы и товар в пути историю не ведут — в ответе они нулевые.
+func (inv *Inventory) BatchGetStockAsOf(ctx context.Context, itemIDs []int64, asOf time.Time) ([]grpcstock.StockDTO, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	want := make(map[int64]map[string]*level, len(itemIDs))
+	for _, id := range itemIDs {
+		want[id] = nil
+	}
+	// Один проход по журналу на весь батч.
+	for _, m := range inv.movements {
+		locs, ok := want[m.ItemID]
+		if !ok || m.At.After(asOf) {
+			continue
+		}
+		if locs == nil {
+			locs = make(map[string]*level)
+			want[m.ItemID] = locs
+		}
+		l, ok := locs[m.LocationCode]
+		if !ok {
+			l = &level{}
+			locs[m.LocationCode] = l
+		}
+		l.onHand, l.updatedAt, l.version = m.After, m.At, l.version+1
+	}
+
+	out := make([]grpcstock.StockDTO, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		if locs := want[id]; locs != nil {
+			out = append(out, toDTO(id, locs))
+		}
+	}
+	return out, nil
+}
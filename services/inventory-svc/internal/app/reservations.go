@@ -0,0 +1,264 @@
+package app
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// reservationRetention — сколько помним завершённые резервы (для идемпотентных Commit/Release).
+const reservationRetention = time.Hour
+
+// Reservation — удержание остатка под checkout. Переходы состояний пишутся в журнал,
+// включая истечение TTL: иначе при replay reserved посчитался бы дважды.
+type Reservation struct {
+	ID           string                     `json:"id"`
+	ItemID       int64                      `json:"item_id"`
+	LocationCode string                     `json:"location_code"`
+	Quantity     int64                      `json:"quantity"`
+	State        grpcstock.ReservationState `json:"state"`
+	Reference    string                     `json:"reference,omitempty"`
+	CreatedAt    time.Time                  `json:"created_at"`
+	ExpiresAt    time.Time                  `json:"expires_at"`
+	FinishedAt   time.Time                  `json:"finished_at,omitzero"` // когда ушёл из ACTIVE
+}
+
+var _ grpcstock.ReservationCommands = (*Inventory)(nil)
+
+func (inv *Inventory) Reserve(ctx context.Context, cmd grpcstock.ReserveCommand) (grpcstock.ReservationDTO, grpcstock.StockDTO, error) {
+	if cmd.Quantity <= 0 {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, errorsx.InvalidArgumentf("quantity must be > 0, got %d", cmd.Quantity)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if err := inv.checkLocation("location_code", cmd.LocationCode); err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	now := inv.now()
+	if err := inv.expireLocked(ctx, now); err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+
+	cur := inv.level(cmd.ItemID, cmd.LocationCode)
+	if free := cur.onHand - cur.reserved; free < cmd.Quantity {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"item %d at %q: insufficient stock to reserve %d (available %d)", cmd.ItemID, cmd.LocationCode, cmd.Quantity, free)
+	}
+
+	r := Reservation{
+		ID:           grpcx.NewUUID(),
+		ItemID:       cmd.ItemID,
+		LocationCode: cmd.LocationCode,
+		Quantity:     cmd.Quantity,
+		State:        grpcstock.ReservationActive,
+		Reference:    cmd.Reference,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(cmd.TTL),
+	}
+	cur.reserved += r.Quantity
+	err := inv.commit(ctx, Entry{
+		Changes:      []Change{toChange(r.ItemID, r.LocationCode, cur)},
+		Reservations: []Reservation{r},
+	})
+	if err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toReservationDTO(r), toDTO(r.ItemID, inv.items[r.ItemID]), nil
+}
+
+func (inv *Inventory) Commit(ctx context.Context, reservationID string) (grpcstock.ReservationDTO, grpcstock.StockDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	now := inv.now()
+	if err := inv.expireLocked(ctx, now); err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	r, err := inv.reservation(reservationID)
+	if err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	switch r.State {
+	case grpcstock.ReservationCommitted:
+		return toReservationDTO(r), toDTO(r.ItemID, inv.items[r.ItemID]), nil
+	case grpcstock.ReservationActive:
+	default:
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"reservation %s is %s and cannot be committed", r.ID, stateName(r.State))
+	}
+
+	cur := inv.level(r.ItemID, r.LocationCode)
+	next := cur.withOnHand(cur.onHand-r.Quantity, now)
+	next.reserved -= r.Quantity
+	r.State, r.FinishedAt = grpcstock.ReservationCommitted, now
+
+	reference := r.Reference
+	if reference == "" {
+		reference = r.ID
+	}
+	err = inv.commit(ctx, Entry{
+		Changes: []Change{toChange(r.ItemID, r.LocationCode, next)},
+		Movements: []Movement{{
+			ItemID:       r.ItemID,
+			LocationCode: r.LocationCode,
+			Before:       cur.onHand,
+			After:        next.onHand,
+			Reason:       grpcstock.ReasonSale,
+			Reference:    reference,
+			At:           next.updatedAt,
+		}},
+		Reservations: []Reservation{r},
+	})
+	if err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toReservationDTO(r), toDTO(r.ItemID, inv.items[r.ItemID]), nil
+}
+
+func (inv *Inventory) Release(ctx context.Context, reservationID string) (grpcstock.ReservationDTO, grpcstock.StockDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	now := inv.now()
+	if err := inv.expireLocked(ctx, now); err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	r, err := inv.reservation(reservationID)
+	if err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	switch r.State {
+	case grpcstock.ReservationReleased, grpcstock.ReservationExpired:
+		return toReservationDTO(r), toDTO(r.ItemID, inv.items[r.ItemID]), nil
+	case grpcstock.ReservationActive:
+	default:
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"reservation %s is %s and cannot be released", r.ID, stateName(r.State))
+	}
+
+	cur := inv.level(r.ItemID, r.LocationCode)
+	cur.reserved -= r.Quantity
+	r.State, r.FinishedAt = grpcstock.ReservationReleased, now
+	err = inv.commit(ctx, Entry{
+		Changes:      []Change{toChange(r.ItemID, r.LocationCode, cur)},
+		Reservations: []Reservation{r},
+	})
+	if err != nil {
+		return grpcstock.ReservationDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toReservationDTO(r), toDTO(r.ItemID, inv.items[r.ItemID]), nil
+}
+
+// RunReservationExpiry — фоновая уборка: истекает просроченные резервы (освобождая остаток)
+// и забывает давно завершённые (тоже через журнал — иначе replay вернёт их). Блокируется до отмены ctx.
+func (inv *Inventory) RunReservationExpiry(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			inv.mu.Lock()
+			now := inv.now()
+			if err := inv.expireLocked(ctx, now); err != nil {
+				log.Printf("inventory: expire reservations: %v", err)
+			}
+			if err := inv.purgeLocked(ctx, now); err != nil {
+				log.Printf("inventory: purge reservations: %v", err)
+			}
+			inv.mu.Unlock()
+		}
+	}
+}
+
+// ===== внутреннее =====
+
+// expireLocked переводит все просроченные активные резервы в EXPIRED одной записью журнала.
+func (inv *Inventory) expireLocked(ctx context.Context, now time.Time) error {
+	var e Entry
+	levels := make(map[stockKey]level)
+	var order []stockKey
+	for _, r := range inv.reservations {
+		if r.State != grpcstock.ReservationActive || now.Before(r.ExpiresAt) {
+			continue
+		}
+		k := stockKey{r.ItemID, r.LocationCode}
+		l, ok := levels[k]
+		if !ok {
+			l = inv.level(r.ItemID, r.LocationCode)
+			order = append(order, k)
+		}
+		l.reserved -= r.Quantity
+		levels[k] = l
+
+		expired := *r
+		expired.State, expired.FinishedAt = grpcstock.ReservationExpired, now
+		e.Reservations = append(e.Reservations, expired)
+	}
+	if len(e.Reservations) == 0 {
+		return nil
+	}
+	for _, k := range order {
+		e.Changes = append(e.Changes, toChange(k.itemID, k.location, levels[k]))
+	}
+	return inv.commit(ctx, e)
+}
+
+// purgeLocked забывает резервы, завершённые дольше reservationRetention назад, одной записью журнала.
+func (inv *Inventory) purgeLocked(ctx context.Context, now time.Time) error {
+	var e Entry
+	for id, r := range inv.reservations {
+		if r.State != grpcstock.ReservationActive && now.Sub(r.FinishedAt) > reservationRetention {
+			e.PurgedReservations = append(e.PurgedReservations, id)
+		}
+	}
+	if len(e.PurgedReservations) == 0 {
+		return nil
+	}
+	slices.Sort(e.PurgedReservations) // порядок map случаен — журнал пусть будет детерминированным
+	return inv.commit(ctx, e)
+}
+
+func (inv *Inventory) reservation(id string) (Reservation, error) {
+	r, ok := inv.reservations[id]
+	if !ok {
+		return Reservation{}, errorsx.NotFoundf("reservation %s", id)
+	}
+	return *r, nil
+}
+
+func toReservationDTO(r Reservation) grpcstock.ReservationDTO {
+	return grpcstock.ReservationDTO{
+		ID:           r.ID,
+		ItemID:       r.ItemID,
+		LocationCode: r.LocationCode,
+		Quantity:     r.Quantity,
+		State:        r.State,
+		Reference:    r.Reference,
+		CreatedAt:    r.CreatedAt,
+		ExpiresAt:    r.ExpiresAt,
+	}
+}
+
+func stateName(s grpcstock.ReservationState) string {
+	switch s {
+	case grpcstock.ReservationActive:
+		return "active"
+	case grpcstock.ReservationCommitted:
+		return "committed"
+	case grpcstock.ReservationReleased:
+		return "released"
+	case grpcstock.ReservationExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
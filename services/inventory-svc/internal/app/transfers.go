@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/YanMak/ecommerce/v2/pkg/errorsx"
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// Transfer — перемещение между локациями. Обе стороны и само перемещение
+// пишутся одной записью журнала, движения обеих сторон — под одним reference.
+type Transfer struct {
+	ID        string                  `json:"id"`
+	ItemID    int64                   `json:"item_id"`
+	From      string                  `json:"from_location_code"`
+	To        string                  `json:"to_location_code"`
+	Quantity  int64                   `json:"quantity"`
+	State     grpcstock.TransferState `json:"state"`
+	Reference string                  `json:"reference,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+var _ grpcstock.TransferCommands = (*Inventory)(nil)
+
+func (inv *Inventory) Transfer(ctx context.Context, cmd grpcstock.TransferCommand) (grpcstock.TransferDTO, grpcstock.StockDTO, error) {
+	switch {
+	case cmd.Quantity <= 0:
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, errorsx.InvalidArgumentf("quantity must be > 0, got %d", cmd.Quantity)
+	case cmd.From == cmd.To:
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, errorsx.InvalidArgumentf("cannot transfer %q to itself", cmd.From)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if err := inv.checkLocation("from_location_code", cmd.From); err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	if err := inv.checkLocation("to_location_code", cmd.To); err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	pre := grpcstock.Precondition{ExpectedVersion: cmd.ExpectedFromVersion}
+	if err := inv.checkPrecondition(cmd.ItemID, cmd.From, pre); err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	now := inv.now()
+	if err := inv.expireLocked(ctx, now); err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+
+	src := inv.level(cmd.ItemID, cmd.From)
+	if free := src.onHand - src.reserved; free < cmd.Quantity {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"item %d at %q: insufficient stock to transfer %d (available %d)", cmd.ItemID, cmd.From, cmd.Quantity, free)
+	}
+
+	t := Transfer{
+		ID:        grpcx.NewUUID(),
+		ItemID:    cmd.ItemID,
+		From:      cmd.From,
+		To:        cmd.To,
+		Quantity:  cmd.Quantity,
+		Reference: cmd.Reference,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	nextSrc := src.withOnHand(src.onHand-t.Quantity, now)
+	moves := []Movement{t.movement(t.From, src, nextSrc, cmd.Actor)}
+
+	dst := inv.level(cmd.ItemID, cmd.To)
+	var nextDst level
+	if cmd.InTransit {
+		t.State = grpcstock.TransferInTransit
+		nextDst = dst
+		nextDst.inTransit += t.Quantity
+	} else {
+		t.State = grpcstock.TransferReceived
+		nextDst = dst.withOnHand(dst.onHand+t.Quantity, now)
+		moves = append(moves, t.movement(t.To, dst, nextDst, cmd.Actor))
+	}
+
+	err := inv.commit(ctx, Entry{
+		Changes: []Change{
+			toChange(t.ItemID, t.From, nextSrc),
+			toChange(t.ItemID, t.To, nextDst),
+		},
+		Movements: moves,
+		Transfers: []Transfer{t},
+	})
+	if err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toTransferDTO(t), toDTO(t.ItemID, inv.items[t.ItemID]), nil
+}
+
+// ReceiveTransfer не проверяет статус локации: товар уже физически приехал,
+// а запрет на запись в INACTIVE касается новых операций.
+func (inv *Inventory) ReceiveTransfer(ctx context.Context, transferID, actor string) (grpcstock.TransferDTO, grpcstock.StockDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	t, err := inv.transfer(transferID)
+	if err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	switch t.State {
+	case grpcstock.TransferReceived:
+		return toTransferDTO(t), toDTO(t.ItemID, inv.items[t.ItemID]), nil
+	case grpcstock.TransferInTransit:
+	default:
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"transfer %s is %s and cannot be received", t.ID, transferStateName(t.State))
+	}
+
+	now := inv.now()
+	dst := inv.level(t.ItemID, t.To)
+	next := dst.withOnHand(dst.onHand+t.Quantity, now)
+	next.inTransit -= t.Quantity
+	t.State, t.UpdatedAt = grpcstock.TransferReceived, now
+	err = inv.commit(ctx, Entry{
+		Changes:   []Change{toChange(t.ItemID, t.To, next)},
+		Movements: []Movement{t.movement(t.To, dst, next, actor)},
+		Transfers: []Transfer{t},
+	})
+	if err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toTransferDTO(t), toDTO(t.ItemID, inv.items[t.ItemID]), nil
+}
+
+// CancelTransfer возвращает товар в пути на источник; источник должен быть зарегистрирован и активен
+// (см. checkLocation), иначе перемещение остаётся в пути.
+func (inv *Inventory) CancelTransfer(ctx context.Context, transferID, actor string) (grpcstock.TransferDTO, grpcstock.StockDTO, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	t, err := inv.transfer(transferID)
+	if err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	switch t.State {
+	case grpcstock.TransferCancelled:
+		return toTransferDTO(t), toDTO(t.ItemID, inv.items[t.ItemID]), nil
+	case grpcstock.TransferInTransit:
+	default:
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, errorsx.FailedPreconditionf(
+			"transfer %s is %s and cannot be cancelled", t.ID, transferStateName(t.State))
+	}
+	// Товар возвращается на источник — он должен быть всё ещё зарегистрирован и активен.
+	if err := inv.checkLocation("from_location_code", t.From); err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+
+	now := inv.now()
+	dst := inv.level(t.ItemID, t.To)
+	dst.inTransit -= t.Quantity
+	src := inv.level(t.ItemID, t.From)
+	nextSrc := src.withOnHand(src.onHand+t.Quantity, now)
+	t.State, t.UpdatedAt = grpcstock.TransferCancelled, now
+	err = inv.commit(ctx, Entry{
+		Changes: []Change{
+			toChange(t.ItemID, t.From, nextSrc),
+			toChange(t.ItemID, t.To, dst),
+		},
+		Movements: []Movement{t.movement(t.From, src, nextSrc, actor)},
+		Transfers: []Transfer{t},
+	})
+	if err != nil {
+		return grpcstock.TransferDTO{}, grpcstock.StockDTO{}, err
+	}
+	return toTransferDTO(t), toDTO(t.ItemID, inv.items[t.ItemID]), nil
+}
+
+// ===== внутреннее =====
+
+// movement — движение одной стороны перемещения; reference по умолчанию — ID перемещения.
+func (t Transfer) movement(location string, before, after level, actor string) Movement {
+	reference := t.Reference
+	if reference == "" {
+		reference = t.ID
+	}
+	return Movement{
+		ItemID:       t.ItemID,
+		LocationCode: location,
+		Before:       before.onHand,
+		After:        after.onHand,
+		Reason:       grpcstock.ReasonTransfer,
+		Reference:    reference,
+		Actor:        actor,
+		At:           after.updatedAt,
+	}
+}
+
+func (inv *Inventory) transfer(id string) (Transfer, error) {
+	t, ok := inv.transfers[id]
+	if !ok {
+		return Transfer{}, errorsx.NotFoundf("transfer %s", id)
+	}
+	return *t, nil
+}
+
+func toTransferDTO(t Transfer) grpcstock.TransferDTO {
+	return grpcstock.TransferDTO{
+		ID:        t.ID,
+		ItemID:    t.ItemID,
+		From:      t.From,
+		To:        t.To,
+		Quantity:  t.Quantity,
+		State:     t.State,
+		Reference: t.Reference,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func transferStateName(s grpcstock.TransferState) string {
+	switch s {
+	case grpcstock.TransferInTransit:
+		return "in transit"
+	case grpcstock.TransferReceived:
+		return "received"
+	case grpcstock.TransferCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
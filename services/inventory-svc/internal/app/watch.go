@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+
+	"github.com/YanMak/ecommerce/v2/pkg/grpcx"
+	grpcstock "github.com/YanMak/ecommerce/v2/services/inventory-svc/internal/adapters/inbound/grpc"
+)
+
+// watchHistory — сколько последних записей журнала (минимум) помним для доигрывания подписчикам.
+// Лишнее срезается пачками по watchTrimChunk: сдвиг слайса раз в пачку, а не на каждую запись.
+const (
+	watchHistory   = 10000
+	watchTrimChunk = watchHistory / 4
+)
+
+// watchState — недавняя история изменённых ключей и подписчики. Живёт под Inventory.mu.
+type watchState struct {
+	epoch   string      // новая на каждый запуск процесса
+	from    uint64      // все изменения с seq > from есть в marks
+	marks   []watchMark // по возрастанию seq
+	waiters map[chan struct{}]struct{}
+}
+
+type watchMark struct {
+	seq  uint64
+	keys []stockKey
+}
+
+func newWatchState() watchState {
+	return watchState{epoch: grpcx.NewUUID(), waiters: make(map[chan struct{}]struct{})}
+}
+
+// record запоминает ключи записи и будит подписчиков (не блокируясь).
+func (w *watchState) record(e Entry) {
+	if len(e.Changes) > 0 {
+		keys := make([]stockKey, 0, len(e.Changes))
+		for _, c := range e.Changes {
+			keys = append(keys, stockKey{c.ItemID, c.LocationCode})
+		}
+		w.marks = append(w.marks, watchMark{seq: e.Seq, keys: keys})
+		if n := len(w.marks) - watchHistory; n >= watchTrimChunk {
+			w.from = w.marks[n-1].seq
+			w.marks = append(w.marks[:0], w.marks[n:]...)
+		}
+	}
+	for ch := range w.waiters {
+		select {
+		case ch <- struct{}{}:
+		default: // сигнал уже ждёт — этого достаточно
+		}
+	}
+}
+
+func (w *watchState) reset(seq uint64) {
+	w.from, w.marks = seq, nil
+}
+
+var _ grpcstock.StockWatcher = (*Inventory)(nil)
+
+func (inv *Inventory) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	inv.mu.Lock()
+	inv.watch.waiters[ch] = struct{}{}
+	inv.mu.Unlock()
+	return ch, func() {
+		inv.mu.Lock()
+		delete(inv.watch.waiters, ch)
+		inv.mu.Unlock()
+	}
+}
+
+func (inv *Inventory) StockChanges(ctx context.Context, since grpcstock.WatchCursor, itemIDs []int64, locationCode string) (grpcstock.StockChanges, error) {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	w := &inv.watch
+	out := grpcstock.StockChanges{Cursor: grpcstock.WatchCursor{Epoch: w.epoch, Seq: inv.seq}}
+
+	// Курсор другого запуска, из будущего или старше истории — отдаём всё, что просили.
+	resync := since.Epoch != w.epoch || since.Seq < w.from || since.Seq > inv.seq
+	changed := make(map[int64]bool, len(itemIDs))
+	if !resync {
+		for _, id := range itemIDs {
+			changed[id] = false
+		}
+		for i := len(w.marks) - 1; i >= 0 && w.marks[i].seq > since.Seq; i-- {
+			for _, k := range w.marks[i].keys {
+				if _, want := changed[k.itemID]; want && (locationCode == "" || k.location == locationCode) {
+					changed[k.itemID] = true
+				}
+			}
+		}
+	}
+	for _, id := range itemIDs {
+		if !resync && !changed[id] {
+			continue
+		}
+		if locs, ok := inv.items[id]; ok {
+			out.Stocks = append(out.Stocks, toDTO(id, locs))
+		}
+	}
+	return out, nil
+}